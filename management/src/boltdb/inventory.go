@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/boltdb/bolt"
+	"github.com/contiv/cluster/management/src/inventory"
 	"github.com/contiv/errored"
 )
 
@@ -30,6 +31,10 @@ type Asset struct {
 	Status    string `json:"status"`
 	State     string `json:"state"`
 	StateDesc string `json:"state_desc"`
+	// Attributes are not set by clusterm, but can be provisioned in the
+	// database for an asset before it is discovered. For instance, the
+	// information to access a node's BMC.
+	Attributes map[string]string `json:"attributes,omitempty"`
 }
 
 // Client denotes state for a boltdb client
@@ -172,3 +177,32 @@ func (c *Client) SetAssetStatus(tag, status, state, reason string) error {
 
 	return nil
 }
+
+// SetAssetsStatus sets the status of multiple assets in a single transaction
+func (c *Client) SetAssetsStatus(updates []inventory.AssetStatusUpdate) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(assetsBucket))
+		for _, u := range updates {
+			val := b.Get([]byte(u.Tag))
+			if val == nil {
+				return errored.Errorf("No asset found for name: %s", u.Tag)
+			}
+			var a Asset
+			if err := json.Unmarshal(val, &a); err != nil {
+				return err
+			}
+			a.Status = u.Status
+			a.State = u.State
+			a.StateDesc = u.Reason
+
+			val, err := json.Marshal(a)
+			if err != nil {
+				return errored.Errorf("failed to marshal. Error: %v", err)
+			}
+			if err := b.Put([]byte(u.Tag), val); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,80 @@
+// +build unittest
+
+package boltdb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/contiv/cluster/management/src/inventory"
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type boltdbSuite struct {
+	dir    string
+	client *Client
+}
+
+var _ = Suite(&boltdbSuite{})
+
+func (s *boltdbSuite) SetUpTest(c *C) {
+	var err error
+	s.dir, err = ioutil.TempDir("", "boltdb")
+	c.Assert(err, IsNil)
+	s.client, err = NewClientFromConfig(Config{DBFile: filepath.Join(s.dir, "test.boltdb")})
+	c.Assert(err, IsNil)
+	for _, tag := range []string{"node1", "node2", "node3"} {
+		c.Assert(s.client.CreateAsset(tag, "Unallocated"), IsNil)
+	}
+}
+
+func (s *boltdbSuite) TearDownTest(c *C) {
+	s.client.db.Close()
+	os.RemoveAll(s.dir)
+}
+
+func (s *boltdbSuite) assertAsset(c *C, tag, status, state string) {
+	a, err := s.client.GetAsset(tag)
+	c.Assert(err, IsNil)
+	c.Assert(a.Status, Equals, status)
+	c.Assert(a.State, Equals, state)
+}
+
+func (s *boltdbSuite) TestSetAssetStatus(c *C) {
+	c.Assert(s.client.SetAssetStatus("node1", "Allocated", "Discovered", "node is alive"), IsNil)
+	a, err := s.client.GetAsset("node1")
+	c.Assert(err, IsNil)
+	c.Assert(a, DeepEquals, Asset{Name: "node1", Status: "Allocated", State: "Discovered",
+		StateDesc: "node is alive"})
+
+	c.Assert(s.client.SetAssetStatus("node4", "Allocated", "Discovered", ""), ErrorMatches,
+		"No asset found for name: node4")
+}
+
+func (s *boltdbSuite) TestSetAssetsStatus(c *C) {
+	c.Assert(s.client.SetAssetsStatus([]inventory.AssetStatusUpdate{
+		{Tag: "node1", Status: "Unallocated", State: "Discovered", Reason: "node is alive"},
+		{Tag: "node2", Status: "Unallocated", State: "Discovered", Reason: "node is alive"},
+	}), IsNil)
+	s.assertAsset(c, "node1", "Unallocated", "Discovered")
+	s.assertAsset(c, "node2", "Unallocated", "Discovered")
+	s.assertAsset(c, "node3", "Unallocated", "")
+}
+
+func (s *boltdbSuite) TestSetAssetsStatusRollback(c *C) {
+	// a failed update aborts the whole batch, including the updates before it
+	c.Assert(s.client.SetAssetsStatus([]inventory.AssetStatusUpdate{
+		{Tag: "node1", Status: "Unallocated", State: "Disappeared"},
+		{Tag: "node4", Status: "Unallocated", State: "Disappeared"},
+		{Tag: "node2", Status: "Unallocated", State: "Disappeared"},
+	}), ErrorMatches, "No asset found for name: node4")
+	s.assertAsset(c, "node1", "Unallocated", "")
+	s.assertAsset(c, "node2", "Unallocated", "")
+	_, err := s.client.GetAsset("node4")
+	c.Assert(err, NotNil)
+}
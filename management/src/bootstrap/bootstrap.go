@@ -0,0 +1,68 @@
+package bootstrap
+
+import (
+	"io"
+	"net"
+	"strings"
+
+	"github.com/contiv/errored"
+)
+
+// NodeStatus enumerates the bootstrap status of a bare-metal node
+type NodeStatus string
+
+const (
+	// Install status denotes that the node shall boot into the OS installer on next network boot
+	Install NodeStatus = "install"
+	// Installed status denotes that the OS install has completed on the node and
+	// it has been handed over for discovery
+	Installed NodeStatus = "installed"
+	// Discovered status denotes that the bootstrapped node has been discovered
+	// by the monitoring subsystem
+	Discovered NodeStatus = "discovered"
+)
+
+// Node denotes a bare-metal node registered for bootstrap
+type Node struct {
+	// MAC is the address of the interface the node network boots from
+	MAC string `json:"mac"`
+	// Name is the name of the node's asset in inventory. It is used to look up
+	// the node's BMC info for power control.
+	Name string `json:"name"`
+	// Addr is the management address configured on the node by the OS install
+	Addr string `json:"addr"`
+	// HostGroup, when set, is the host-group the node is commissioned in after discovery
+	HostGroup string `json:"host_group,omitempty"`
+	// ExtraVars are the ansible extra variables used for discovery and commission of the node
+	ExtraVars string     `json:"extra_vars,omitempty"`
+	Status    NodeStatus `json:"status"`
+}
+
+// NormalizeMAC validates and returns the MAC address in canonical lower-case form
+func NormalizeMAC(mac string) (string, error) {
+	hw, err := net.ParseMAC(strings.TrimSpace(mac))
+	if err != nil {
+		return "", errored.Errorf("invalid MAC address %q. Error: %v", mac, err)
+	}
+	return hw.String(), nil
+}
+
+// Subsys provides the following services to the cluster manager:
+// - Interface to register bare-metal nodes and track their OS install.
+// - Serve the per-node network boot and install configuration.
+type Subsys interface {
+	// Register registers the nodes for bootstrap. A registered node is set to Install status.
+	Register(nodes []*Node) error
+	// SetStatus sets the bootstrap status of the node with specified MAC address
+	SetStatus(mac string, status NodeStatus) error
+	// GetNode returns the node registered with specified MAC address
+	GetNode(mac string) (*Node, error)
+	// GetAllNodes returns all the registered nodes
+	GetAllNodes() []*Node
+	// WriteBootScript writes the network boot script for node with specified MAC address.
+	// For nodes that are not in Install status the script boots from the local disk.
+	WriteBootScript(mac string, w io.Writer) error
+	// WriteInstallConfig writes the OS install configuration (like kickstart or preseed)
+	// for node with specified MAC address.
+	WriteInstallConfig(mac string, w io.Writer) error
+}
@@ -0,0 +1,218 @@
+package bootstrap
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"text/template"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/contiv/errored"
+)
+
+const (
+	// ConfigURLPrefix is the url prefix at which clusterm serves the install configuration of a node
+	ConfigURLPrefix = "bootstrap/config"
+	// DoneURLPrefix is the url prefix at which clusterm receives the install completion of a node
+	DoneURLPrefix = "bootstrap/done"
+)
+
+// localBootScript is the iPXE script served to nodes that are not being installed.
+// It makes iPXE exit and lets the firmware boot from the next device i.e. local disk.
+const localBootScript = "#!ipxe\nexit\n"
+
+// Config denotes the configuration for the PXE/iPXE based bootstrap subsystem
+type Config struct {
+	// StateFile is the file where the registered nodes are persisted
+	StateFile string `json:"state_file"`
+	// InstallScript is the iPXE script template that boots a node into OS installer
+	InstallScript string `json:"install_script"`
+	// InstallConfig is the kickstart or preseed template for the OS installer
+	InstallConfig string `json:"install_config"`
+	// ClustermURL is the url at which the nodes being installed can reach clusterm
+	ClustermURL string `json:"clusterm_url"`
+}
+
+// DefaultConfig returns the default configuration values for the bootstrap subsystem
+func DefaultConfig() Config {
+	return Config{
+		StateFile:     "/etc/default/clusterm/bootstrap.json",
+		InstallScript: "/etc/default/clusterm/install.ipxe",
+		InstallConfig: "/etc/default/clusterm/ks.cfg",
+		ClustermURL:   "http://localhost:9007",
+	}
+}
+
+// templateData is the data available to the install script and config templates
+type templateData struct {
+	Node *Node
+	// ConfigURL is the url to fetch the install configuration of the node
+	ConfigURL string
+	// DoneURL is the url to post to, once the OS install completes on the node
+	DoneURL string
+}
+
+// PXESubsys implements the bootstrap subsystem for a PXE/iPXE and kickstart/preseed setup
+type PXESubsys struct {
+	sync.Mutex
+	config Config
+	nodes  map[string]*Node
+}
+
+// NewPXESubsys initializes and returns an instance of the PXE bootstrap subsystem.
+// It restores the nodes registered previously, if any.
+func NewPXESubsys(config Config) (*PXESubsys, error) {
+	s := &PXESubsys{
+		config: config,
+		nodes:  make(map[string]*Node),
+	}
+
+	out, err := ioutil.ReadFile(config.StateFile)
+	if os.IsNotExist(err) {
+		return s, nil
+	} else if err != nil {
+		return nil, errored.Errorf("failed to read bootstrap state. Error: %v", err)
+	}
+	nodes := []*Node{}
+	if err := json.Unmarshal(out, &nodes); err != nil {
+		return nil, errored.Errorf("failed to parse bootstrap state. Error: %v", err)
+	}
+	for _, n := range nodes {
+		s.nodes[n.MAC] = n
+	}
+	return s, nil
+}
+
+// save persists the registered nodes. It is called with lock held.
+func (s *PXESubsys) save() error {
+	out, err := json.Marshal(s.sortedNodes())
+	if err != nil {
+		return errored.Errorf("failed to marshal bootstrap state. Error: %v", err)
+	}
+	// write to a temporary file and rename it to avoid a partially written state
+	tmpFile := s.config.StateFile + ".tmp"
+	if err := ioutil.WriteFile(tmpFile, out, 0600); err != nil {
+		return errored.Errorf("failed to write bootstrap state. Error: %v", err)
+	}
+	return os.Rename(tmpFile, s.config.StateFile)
+}
+
+func (s *PXESubsys) sortedNodes() []*Node {
+	macs := []string{}
+	for mac := range s.nodes {
+		macs = append(macs, mac)
+	}
+	sort.Strings(macs)
+	nodes := []*Node{}
+	for _, mac := range macs {
+		n := *s.nodes[mac]
+		nodes = append(nodes, &n)
+	}
+	return nodes
+}
+
+// Register implements the node registration interface of bootstrap subsystem
+func (s *PXESubsys) Register(nodes []*Node) error {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, n := range nodes {
+		mac, err := NormalizeMAC(n.MAC)
+		if err != nil {
+			return err
+		}
+		node := *n
+		node.MAC = mac
+		node.Status = Install
+		s.nodes[mac] = &node
+		logrus.Debugf("registered node for bootstrap: %+v", node)
+	}
+	return s.save()
+}
+
+// SetStatus implements the set status interface of bootstrap subsystem
+func (s *PXESubsys) SetStatus(mac string, status NodeStatus) error {
+	s.Lock()
+	defer s.Unlock()
+
+	n, err := s.getNode(mac)
+	if err != nil {
+		return err
+	}
+	n.Status = status
+	return s.save()
+}
+
+// getNode returns the registered node. It is called with lock held.
+func (s *PXESubsys) getNode(mac string) (*Node, error) {
+	mac, err := NormalizeMAC(mac)
+	if err != nil {
+		return nil, err
+	}
+	n, ok := s.nodes[mac]
+	if !ok {
+		return nil, errored.Errorf("no node is registered for bootstrap with MAC address %q", mac)
+	}
+	return n, nil
+}
+
+// GetNode implements the get node interface of bootstrap subsystem
+func (s *PXESubsys) GetNode(mac string) (*Node, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	n, err := s.getNode(mac)
+	if err != nil {
+		return nil, err
+	}
+	node := *n
+	return &node, nil
+}
+
+// GetAllNodes implements the get all nodes interface of bootstrap subsystem
+func (s *PXESubsys) GetAllNodes() []*Node {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.sortedNodes()
+}
+
+func (s *PXESubsys) writeTemplate(tmplFile string, n *Node, w io.Writer) error {
+	tmpl, err := template.ParseFiles(tmplFile)
+	if err != nil {
+		return errored.Errorf("failed to parse template %q. Error: %v", tmplFile, err)
+	}
+	data := &templateData{
+		Node:      n,
+		ConfigURL: s.config.ClustermURL + "/" + ConfigURLPrefix + "/" + n.MAC,
+		DoneURL:   s.config.ClustermURL + "/" + DoneURLPrefix + "/" + n.MAC,
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		return errored.Errorf("failed to execute template %q. Error: %v", filepath.Base(tmplFile), err)
+	}
+	return nil
+}
+
+// WriteBootScript implements the boot script interface of bootstrap subsystem
+func (s *PXESubsys) WriteBootScript(mac string, w io.Writer) error {
+	n, err := s.GetNode(mac)
+	if err != nil || n.Status != Install {
+		// nodes that are not known or not being installed boot from local disk
+		_, err := io.WriteString(w, localBootScript)
+		return err
+	}
+	return s.writeTemplate(s.config.InstallScript, n, w)
+}
+
+// WriteInstallConfig implements the install config interface of bootstrap subsystem
+func (s *PXESubsys) WriteInstallConfig(mac string, w io.Writer) error {
+	n, err := s.GetNode(mac)
+	if err != nil {
+		return err
+	}
+	return s.writeTemplate(s.config.InstallConfig, n, w)
+}
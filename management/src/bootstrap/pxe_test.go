@@ -0,0 +1,116 @@
+// +build unittest
+
+package bootstrap
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type pxeSuite struct {
+	tmpDir string
+	config Config
+}
+
+var _ = Suite(&pxeSuite{})
+
+func (s *pxeSuite) SetUpTest(c *C) {
+	var err error
+	s.tmpDir, err = ioutil.TempDir("", "bootstrap")
+	c.Assert(err, IsNil)
+	s.config = Config{
+		StateFile:     filepath.Join(s.tmpDir, "bootstrap.json"),
+		InstallScript: filepath.Join(s.tmpDir, "install.ipxe"),
+		InstallConfig: filepath.Join(s.tmpDir, "ks.cfg"),
+		ClustermURL:   "http://1.2.3.4:9007",
+	}
+	c.Assert(ioutil.WriteFile(s.config.InstallScript,
+		[]byte("#!ipxe\nkernel vmlinuz ks={{ .ConfigURL }}\n"), 0600), IsNil)
+	c.Assert(ioutil.WriteFile(s.config.InstallConfig,
+		[]byte("network --ip={{ .Node.Addr }}\ncurl {{ .DoneURL }}\n"), 0600), IsNil)
+}
+
+func (s *pxeSuite) TearDownTest(c *C) {
+	os.RemoveAll(s.tmpDir)
+}
+
+func (s *pxeSuite) TestNormalizeMAC(c *C) {
+	mac, err := NormalizeMAC(" 52:54:00:AB:CD:EF ")
+	c.Assert(err, IsNil)
+	c.Assert(mac, Equals, "52:54:00:ab:cd:ef")
+
+	_, err = NormalizeMAC("foo")
+	c.Assert(err, NotNil)
+}
+
+func (s *pxeSuite) TestRegisterAndRestore(c *C) {
+	subsys, err := NewPXESubsys(s.config)
+	c.Assert(err, IsNil)
+	c.Assert(subsys.Register([]*Node{
+		{MAC: "52:54:00:AB:CD:EF", Name: "node1", Addr: "10.0.0.1", Status: Installed},
+	}), IsNil)
+
+	n, err := subsys.GetNode("52:54:00:ab:cd:ef")
+	c.Assert(err, IsNil)
+	c.Assert(n.Name, Equals, "node1")
+	c.Assert(n.Status, Equals, Install)
+	c.Assert(subsys.SetStatus(n.MAC, Installed), IsNil)
+
+	// the nodes are restored from the state file
+	subsys, err = NewPXESubsys(s.config)
+	c.Assert(err, IsNil)
+	nodes := subsys.GetAllNodes()
+	c.Assert(len(nodes), Equals, 1)
+	c.Assert(*nodes[0], DeepEquals, Node{MAC: "52:54:00:ab:cd:ef", Name: "node1", Addr: "10.0.0.1",
+		Status: Installed})
+}
+
+func (s *pxeSuite) TestRegisterInvalidMAC(c *C) {
+	subsys, err := NewPXESubsys(s.config)
+	c.Assert(err, IsNil)
+	c.Assert(subsys.Register([]*Node{{MAC: "foo"}}), NotNil)
+	c.Assert(len(subsys.GetAllNodes()), Equals, 0)
+}
+
+func (s *pxeSuite) TestWriteBootScript(c *C) {
+	subsys, err := NewPXESubsys(s.config)
+	c.Assert(err, IsNil)
+	c.Assert(subsys.Register([]*Node{{MAC: "52:54:00:ab:cd:ef", Name: "node1", Addr: "10.0.0.1"}}), IsNil)
+
+	var out bytes.Buffer
+	c.Assert(subsys.WriteBootScript("52:54:00:ab:cd:ef", &out), IsNil)
+	c.Assert(out.String(), Equals,
+		"#!ipxe\nkernel vmlinuz ks=http://1.2.3.4:9007/bootstrap/config/52:54:00:ab:cd:ef\n")
+
+	// node that is installed shall boot from local disk
+	c.Assert(subsys.SetStatus("52:54:00:ab:cd:ef", Installed), IsNil)
+	out.Reset()
+	c.Assert(subsys.WriteBootScript("52:54:00:ab:cd:ef", &out), IsNil)
+	c.Assert(out.String(), Equals, localBootScript)
+
+	// unknown node shall boot from local disk
+	out.Reset()
+	c.Assert(subsys.WriteBootScript("52:54:00:00:00:01", &out), IsNil)
+	c.Assert(out.String(), Equals, localBootScript)
+}
+
+func (s *pxeSuite) TestWriteInstallConfig(c *C) {
+	subsys, err := NewPXESubsys(s.config)
+	c.Assert(err, IsNil)
+	c.Assert(subsys.Register([]*Node{{MAC: "52:54:00:ab:cd:ef", Name: "node1", Addr: "10.0.0.1"}}), IsNil)
+
+	var out bytes.Buffer
+	c.Assert(subsys.WriteInstallConfig("52:54:00:ab:cd:ef", &out), IsNil)
+	c.Assert(out.String(), Equals,
+		"network --ip=10.0.0.1\ncurl http://1.2.3.4:9007/bootstrap/done/52:54:00:ab:cd:ef\n")
+
+	c.Assert(subsys.WriteInstallConfig("52:54:00:00:00:01", &out), NotNil)
+}
@@ -52,6 +52,34 @@ var (
 		},
 	}
 
+	postDiscoverFlags = []cli.Flag{
+		extraVarsFlag,
+		cli.StringSliceFlag{
+			Name:  "power-on, p",
+			Value: &cli.StringSlice{},
+			Usage: "name of an inventory asset to power on before it is provisioned for discovery. Can be repeated",
+		},
+	}
+
+	postBootstrapRegisterFlags = []cli.Flag{
+		extraVarsFlag,
+		cli.StringFlag{
+			Name:  "name, n",
+			Value: "",
+			Usage: "name of the node's asset in inventory. It is used to look up node's BMC info for power control",
+		},
+		cli.StringFlag{
+			Name:  "addr, a",
+			Value: "",
+			Usage: "management address that is configured on the node by the OS install",
+		},
+		cli.StringFlag{
+			Name:  "host-group, g",
+			Value: "",
+			Usage: "host-group to commission the node in, once it is discovered. Possible values: service-master or service-worker. The node is not commissioned if it is not specified",
+		},
+	}
+
 	commands = []cli.Command{
 		{
 			Name:    "node",
@@ -163,7 +191,34 @@ var (
 			Aliases: []string{"d"},
 			Usage:   "provision one or more nodes for discovery",
 			Action:  doAction(newPostActioner(validateMultiNodeAddrs, nodesDiscover)),
-			Flags:   postFlags,
+			Flags:   postDiscoverFlags,
+		},
+		{
+			Name:    "bootstrap",
+			Aliases: []string{"b"},
+			Usage:   "bare-metal node bootstrap related operation",
+			Subcommands: []cli.Command{
+				{
+					Name:    "register",
+					Aliases: []string{"r"},
+					Usage:   "register a bare-metal node for bootstrap. Expects an arg with the MAC address the node network boots from",
+					Action:  doAction(newPostActioner(validateOneArg, bootstrapRegister)),
+					Flags:   postBootstrapRegisterFlags,
+				},
+				{
+					Name:    "reimage",
+					Aliases: []string{"i"},
+					Usage:   "reimage a set of bare-metal nodes registered for bootstrap",
+					Action:  doAction(newPostActioner(validateMultiNodeNames, bootstrapReimage)),
+				},
+				{
+					Name:    "get",
+					Aliases: []string{"g"},
+					Usage:   "get info for all bare-metal nodes registered for bootstrap",
+					Action:  doAction(newGetActioner(bootstrapGet)),
+					Flags:   getFlags,
+				},
+			},
 		},
 		{
 			Name:    "config",
@@ -197,10 +252,13 @@ func errInvalidIPAddr(a string) error {
 }
 
 type parsedFlags struct {
-	extraVars  string
-	hostGroup  string
-	jsonOutput bool
-	streamLogs bool
+	extraVars    string
+	hostGroup    string
+	powerOnNodes []string
+	nodeName     string
+	nodeAddr     string
+	jsonOutput   bool
+	streamLogs   bool
 }
 
 type actioner interface {
@@ -27,6 +27,8 @@ type globalInfo map[string]interface{}
 
 type configInfo map[string]interface{}
 
+type bootstrapInfo map[string]interface{}
+
 // printHelper stores indent related metadat along with the value being printed
 type printHelper struct {
 	Indent string
@@ -97,6 +99,9 @@ var (
 	configPrint    = `{{ template "typePrint" newPrintHelper "" .}}`
 	configTemplate = template.Must(template.Must(typeTemplate.Clone()).Parse(configPrint))
 
+	bootstrapPrint    = `{{ template "typePrint" newPrintHelper "" .}}`
+	bootstrapTemplate = template.Must(template.Must(typeTemplate.Clone()).Parse(bootstrapPrint))
+
 	nodePrint = `
 {{- define "nodePrint" }}
 	{{- $invName := .Inv.name }}
@@ -118,18 +123,35 @@ var (
 	multiNodeTemplate = template.Must(template.Must(nodeTemplate.Clone()).Parse(multiNodePrint))
 
 	jobPrint = `
+ID: {{ .id }}
 Description: {{ .desc }}
+{{- if .commit }}
+Commit: {{ .commit }}
+{{- end }}
 Status: {{ .status }}
 Error: {{ .error }}
+{{- if .error_info }}
+Error Code: {{ .error_info.code }}
+{{- end }}
+{{- if .log_file }}
+Log File: {{ .log_file }}
+{{- end }}
 Logs:
 {{ template "typePrint" newPrintHelper "    " .logs }}
 `
 	jobTemplate = template.Must(template.Must(typeTemplate.Clone()).Parse(jobPrint))
 
 	shortJobPrint = `
+ID: {{ .id }}
 Description: {{ .desc }}
+{{- if .commit }}
+Commit: {{ .commit }}
+{{- end }}
 Status: {{ .status }}
 Error: {{ .error }}
+{{- if .error_info }}
+Error Code: {{ .error_info.code }}
+{{- end }}
 `
 	shortJobTemplate = template.Must(template.Must(typeTemplate.Clone()).Parse(shortJobPrint))
 )
@@ -276,3 +298,16 @@ func configGet(c *manager.Client, noop string, flags parsedFlags) error {
 
 	return ppJSON(out)
 }
+
+func bootstrapGet(c *manager.Client, noop string, flags parsedFlags) error {
+	out, err := c.GetBootstrapNodes()
+	if err != nil {
+		return err
+	}
+
+	if !flags.jsonOutput {
+		return printTemplate(out, bootstrapTemplate, &bootstrapInfo{})
+	}
+
+	return ppJSON(out)
+}
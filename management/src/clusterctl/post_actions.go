@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"github.com/codegangsta/cli"
+	"github.com/contiv/cluster/management/src/bootstrap"
 	"github.com/contiv/cluster/management/src/clusterm/manager"
 	"github.com/contiv/errored"
 )
@@ -31,6 +32,9 @@ func newPostActioner(validateCb validateCallback, postCb postCallback) *postActi
 func (npa *postActioner) procFlags(c *cli.Context) {
 	npa.flags.extraVars = c.String("extra-vars")
 	npa.flags.hostGroup = c.String("host-group")
+	npa.flags.powerOnNodes = c.StringSlice("power-on")
+	npa.flags.nodeName = c.String("name")
+	npa.flags.nodeAddr = c.String("addr")
 }
 
 func (npa *postActioner) procArgs(c *cli.Context) {
@@ -98,7 +102,23 @@ func validateMultiNodeAddrs(args []string) error {
 }
 
 func nodesDiscover(c *manager.Client, args []string, flags parsedFlags) error {
-	return c.PostNodesDiscover(args, flags.extraVars)
+	return c.PostNodesDiscoverWithPowerOn(args, flags.powerOnNodes, flags.extraVars)
+}
+
+func bootstrapRegister(c *manager.Client, args []string, flags parsedFlags) error {
+	return c.PostBootstrapNodes([]*bootstrap.Node{
+		{
+			MAC:       args[0],
+			Name:      flags.nodeName,
+			Addr:      flags.nodeAddr,
+			HostGroup: flags.hostGroup,
+			ExtraVars: flags.extraVars,
+		},
+	})
+}
+
+func bootstrapReimage(c *manager.Client, args []string, noop parsedFlags) error {
+	return c.PostBootstrapReimage(args)
 }
 
 func validateZeroArgs(args []string) error {
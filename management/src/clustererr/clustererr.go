@@ -0,0 +1,211 @@
+// Package clustererr implements the taxonomy of the errors encountered by the
+// cluster manager and it's subsystems. Every error carries a stable code that
+// denotes it's category and optionally the node it occurred for. The code is
+// retained as the error is propagated, so that it can be reported as is in the
+// api responses and the job records.
+package clustererr
+
+import (
+	"net"
+	"sort"
+
+	"github.com/contiv/errored"
+	"golang.org/x/net/context"
+)
+
+// Code denotes the category of an error. The codes are reported in the api
+// responses and job records, so these shall not be changed once released.
+type Code string
+
+const (
+	// Internal is the code of the errors that don't fall in any other category
+	Internal Code = "internal"
+	// Validation is the code of the errors due to an invalid request, like a
+	// non-existent node or an invalid host-group
+	Validation Code = "validation"
+	// Conflict is the code of the errors due to a request that conflicts with
+	// the current state, like an active job or a disallowed status transition
+	Conflict Code = "conflict"
+	// Inventory is the code of the errors encountered by the inventory backend
+	Inventory Code = "inventory_backend"
+	// Provisioner is the code of the errors encountered by the configuration
+	// subsystem while provisioning the nodes
+	Provisioner Code = "provisioner"
+	// Monitor is the code of the errors encountered by the monitoring subsystem
+	Monitor Code = "monitor"
+	// Timeout is the code of the errors due to an operation that timed out
+	Timeout Code = "timeout"
+)
+
+// Error is an error of a category, that may have occurred for a node
+type Error struct {
+	code Code
+	node string
+	err  error
+}
+
+// New returns an error of the code
+func New(code Code, format string, args ...interface{}) *Error {
+	return &Error{code: code, err: errored.Errorf(format, args...)}
+}
+
+// NewForNode returns an error of the code, that occurred for the node
+func NewForNode(code Code, node, format string, args ...interface{}) *Error {
+	return &Error{code: code, node: node, err: errored.Errorf(format, args...)}
+}
+
+// Wrap returns an error of the code, that wraps err and occurred for the node.
+// The code and node of err are retained, if it already has them. An err due to
+// a timeout is reported with the Timeout code. It returns nil if err is nil.
+func Wrap(code Code, node string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(*Error); ok {
+		if e.node != "" || node == "" {
+			return e
+		}
+		return &Error{code: e.code, node: node, err: e.err}
+	}
+	if _, ok := err.(aggregate); ok {
+		return err
+	}
+	if isTimeout(err) {
+		code = Timeout
+	}
+	return &Error{code: code, node: node, err: err}
+}
+
+// Wrapf is like Wrap, but the returned error is described by the format. The
+// format is expected to include err in the description.
+func Wrapf(code Code, node string, err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	if c := CodeOf(err); c != Internal {
+		code = c
+	} else if isTimeout(err) {
+		code = Timeout
+	}
+	if n := NodeOf(err); n != "" {
+		node = n
+	}
+	return &Error{code: code, node: node, err: errored.Errorf(format, args...)}
+}
+
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+// Code returns the code of the error
+func (e *Error) Code() Code {
+	return e.code
+}
+
+// Node returns the name of the node the error occurred for, if any
+func (e *Error) Node() string {
+	return e.node
+}
+
+// aggregate is implemented by the errors that aggregate the errors of multiple
+// nodes, keyed by the node name
+type aggregate interface {
+	error
+	Errors() map[string]error
+}
+
+func isTimeout(err error) bool {
+	if err == context.DeadlineExceeded {
+		return true
+	}
+	nerr, ok := err.(net.Error)
+	return ok && nerr.Timeout()
+}
+
+// CodeOf returns the code of an error. The code of an aggregate of errors is
+// the code shared by all of it's errors, or Internal if they differ. The errors
+// that don't carry a code are Internal, unless they are due to a timeout.
+func CodeOf(err error) Code {
+	switch e := err.(type) {
+	case nil:
+		return ""
+	case *Error:
+		return e.code
+	case *Info:
+		return e.Code
+	case aggregate:
+		code := Code("")
+		for _, nerr := range e.Errors() {
+			c := CodeOf(nerr)
+			if code != "" && c != code {
+				return Internal
+			}
+			code = c
+		}
+		if code == "" {
+			return Internal
+		}
+		return code
+	}
+	if isTimeout(err) {
+		return Timeout
+	}
+	return Internal
+}
+
+// NodeOf returns the name of the node an error occurred for, if any
+func NodeOf(err error) string {
+	switch e := err.(type) {
+	case *Error:
+		return e.node
+	case *Info:
+		return e.Node
+	}
+	return ""
+}
+
+// Info is the json encodable information of an error, as reported in the api
+// responses and job records
+type Info struct {
+	Code    Code   `json:"code"`
+	Message string `json:"message"`
+	Node    string `json:"node,omitempty"`
+	// Nodes are the errors of the individual nodes, when the error aggregates
+	// the errors of multiple nodes
+	Nodes []*Info `json:"nodes,omitempty"`
+}
+
+// InfoOf returns the information of an error. It returns nil if err is nil.
+func InfoOf(err error) *Info {
+	if err == nil {
+		return nil
+	}
+	if i, ok := err.(*Info); ok {
+		return i
+	}
+	info := &Info{
+		Code:    CodeOf(err),
+		Message: err.Error(),
+		Node:    NodeOf(err),
+	}
+	if agg, ok := err.(aggregate); ok {
+		errs := agg.Errors()
+		names := []string{}
+		for name := range errs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			ninfo := *InfoOf(errs[name])
+			if ninfo.Node == "" {
+				ninfo.Node = name
+			}
+			info.Nodes = append(info.Nodes, &ninfo)
+		}
+	}
+	return info
+}
+
+func (i *Info) Error() string {
+	return i.Message
+}
@@ -0,0 +1,130 @@
+// +build unittest
+
+package clustererr
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/contiv/errored"
+	"golang.org/x/net/context"
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type errSuite struct {
+}
+
+var _ = Suite(&errSuite{})
+
+type testNodeErrors map[string]error
+
+func (e testNodeErrors) Error() string {
+	return "node(s) failed"
+}
+
+func (e testNodeErrors) Errors() map[string]error {
+	return e
+}
+
+type timeoutErr struct{}
+
+func (e timeoutErr) Error() string   { return "i/o timeout" }
+func (e timeoutErr) Timeout() bool   { return true }
+func (e timeoutErr) Temporary() bool { return true }
+
+var _ net.Error = timeoutErr{}
+
+func (s *errSuite) TestNew(c *C) {
+	err := New(Validation, "invalid host-group %q", "foo")
+	c.Assert(err, ErrorMatches, `invalid host-group "foo"`)
+	c.Assert(CodeOf(err), Equals, Validation)
+	c.Assert(NodeOf(err), Equals, "")
+
+	err = NewForNode(Conflict, "node1", "node %q is busy", "node1")
+	c.Assert(err, ErrorMatches, `node "node1" is busy`)
+	c.Assert(CodeOf(err), Equals, Conflict)
+	c.Assert(NodeOf(err), Equals, "node1")
+}
+
+func (s *errSuite) TestWrap(c *C) {
+	c.Assert(Wrap(Inventory, "node1", nil), IsNil)
+
+	// an error without code gets the code and node
+	err := Wrap(Inventory, "node1", errored.Errorf("connection refused"))
+	c.Assert(err, ErrorMatches, "connection refused")
+	c.Assert(CodeOf(err), Equals, Inventory)
+	c.Assert(NodeOf(err), Equals, "node1")
+
+	// the code of an error is retained and the node is set, if it has none
+	err = Wrap(Inventory, "node1", New(Conflict, "transition not allowed"))
+	c.Assert(CodeOf(err), Equals, Conflict)
+	c.Assert(NodeOf(err), Equals, "node1")
+	err = Wrap(Inventory, "node2", err)
+	c.Assert(NodeOf(err), Equals, "node1")
+
+	// timeouts are reported as such
+	c.Assert(CodeOf(Wrap(Inventory, "", timeoutErr{})), Equals, Timeout)
+	c.Assert(CodeOf(Wrap(Provisioner, "", context.DeadlineExceeded)), Equals, Timeout)
+	c.Assert(CodeOf(timeoutErr{}), Equals, Timeout)
+	c.Assert(CodeOf(errored.Errorf("foo")), Equals, Internal)
+}
+
+func (s *errSuite) TestWrapf(c *C) {
+	c.Assert(Wrapf(Inventory, "node1", nil, "failed"), IsNil)
+
+	cause := NewForNode(Conflict, "node2", "transition not allowed")
+	err := Wrapf(Inventory, "node1", cause, "failed to update node1. Error: %v", cause)
+	c.Assert(err, ErrorMatches, "failed to update node1. Error: transition not allowed")
+	c.Assert(CodeOf(err), Equals, Conflict)
+	c.Assert(NodeOf(err), Equals, "node2")
+
+	err = Wrapf(Inventory, "node1", errored.Errorf("refused"), "failed to update node1")
+	c.Assert(CodeOf(err), Equals, Inventory)
+	c.Assert(NodeOf(err), Equals, "node1")
+}
+
+func (s *errSuite) TestAggregate(c *C) {
+	err := testNodeErrors{
+		"node2": New(Inventory, "refused"),
+		"node1": NewForNode(Inventory, "node1", "timed out"),
+	}
+	c.Assert(CodeOf(err), Equals, Inventory)
+	c.Assert(Wrap(Provisioner, "", err), DeepEquals, err)
+
+	err["node3"] = errored.Errorf("foo")
+	c.Assert(CodeOf(err), Equals, Internal)
+	c.Assert(CodeOf(testNodeErrors{}), Equals, Internal)
+}
+
+func (s *errSuite) TestInfo(c *C) {
+	c.Assert(InfoOf(nil), IsNil)
+
+	err := testNodeErrors{
+		"node2": New(Validation, "node doesn't exist"),
+		"node1": NewForNode(Conflict, "node1", "node is not discovered"),
+	}
+	info := InfoOf(err)
+	c.Assert(info, DeepEquals, &Info{
+		Code:    Internal,
+		Message: "node(s) failed",
+		Nodes: []*Info{
+			{Code: Conflict, Message: "node is not discovered", Node: "node1"},
+			{Code: Validation, Message: "node doesn't exist", Node: "node2"},
+		},
+	})
+
+	// the info is retained when it is encoded
+	out, jerr := json.Marshal(info)
+	c.Assert(jerr, IsNil)
+	decoded := &Info{}
+	c.Assert(json.Unmarshal(out, decoded), IsNil)
+	c.Assert(decoded, DeepEquals, info)
+	c.Assert(decoded, ErrorMatches, "node\\(s\\) failed")
+	c.Assert(CodeOf(decoded.Nodes[0]), Equals, Conflict)
+	c.Assert(NodeOf(decoded.Nodes[0]), Equals, "node1")
+	c.Assert(InfoOf(decoded), Equals, decoded)
+}
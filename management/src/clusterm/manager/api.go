@@ -11,9 +11,10 @@ import (
 	"strings"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/contiv/cluster/management/src/bootstrap"
+	"github.com/contiv/cluster/management/src/clustererr"
 	"github.com/contiv/cluster/management/src/configuration"
 	"github.com/contiv/cluster/management/src/monitor"
-	"github.com/contiv/errored"
 	"github.com/gorilla/mux"
 )
 
@@ -39,35 +40,38 @@ type APIRequest struct {
 	Job       string       `json:"job,omitempty"`
 	Event     MonitorEvent `json:"monitor_event,omitempty"`
 	Config    *Config      `json:"config,omitempty"`
+	MAC       string       `json:"mac,omitempty"`
+	// BootstrapNodes are the bare-metal nodes to register for bootstrap
+	BootstrapNodes []*bootstrap.Node `json:"bootstrap_nodes,omitempty"`
 }
 
 // errInvalidJSON is the error returned when an invalid json value is specified for
 // the ansible extra variables configuration
 func errInvalidJSON(name string, err error) error {
-	return errored.Errorf("%q should be a valid json. Error: %s", name, err)
+	return clustererr.New(clustererr.Validation, "%q should be a valid json. Error: %s", name, err)
 }
 
 // errJobNotExist is the error returned when a job with specified label doesn't exists
 func errJobNotExist(job string) error {
-	return errored.Errorf("info for %q job doesn't exist", job)
+	return clustererr.New(clustererr.Validation, "info for %q job doesn't exist", job)
 }
 
 // errInvalidJobLabel is the error returned when an invalid or empty job label
 // is specified as part of job info request
 func errInvalidJobLabel(job string) error {
-	return errored.Errorf("Invalid or empty job label specified: %q", job)
+	return clustererr.New(clustererr.Validation, "Invalid or empty job label specified: %q", job)
 }
 
 // errInvalidEventName is the error returned when an invalid or empty event name
 // is specified as part of monitor event request
 func errInvalidEventName(event string) error {
-	return errored.Errorf("Invalid or empty event name specified: %q", event)
+	return clustererr.New(clustererr.Validation, "Invalid or empty event name specified: %q", event)
 }
 
 // errNilConfig is the error returned when a nil configuration value is
 // specified as part of clusterm configuration update request
 func errNilConfig() error {
-	return errored.Errorf("nil value specified for clusterm configuration")
+	return clustererr.New(clustererr.Validation, "nil value specified for clusterm configuration")
 }
 
 func (m *Manager) apiLoop(errCh chan error, servingCh chan struct{}) {
@@ -87,6 +91,9 @@ func (m *Manager) apiLoop(errCh chan error, servingCh chan struct{}) {
 			{"/" + getJob, emptyHdrs, get(m.jobGet)},
 			{"/" + getJobLog, emptyHdrs, get(m.logsGet)},
 			{"/" + GetPostConfig, emptyHdrs, get(m.configGet)},
+			{"/" + getBootstrapScript, emptyHdrs, get(m.bootstrapScriptGet)},
+			{"/" + getBootstrapConfig, emptyHdrs, get(m.bootstrapConfigGet)},
+			{"/" + GetBootstrapNodes, emptyHdrs, get(m.bootstrapNodesGet)},
 			{"/" + getDebugPrefix + "/", emptyHdrs, pprof.Index},
 			{"/" + getDebugPrefix + "/cmdline", emptyHdrs, pprof.Cmdline},
 			{"/" + getDebugPrefix + "/profile", emptyHdrs, pprof.Profile},
@@ -102,6 +109,9 @@ func (m *Manager) apiLoop(errCh chan error, servingCh chan struct{}) {
 			{"/" + PostGlobals, jsonContentHdrs, post(m.globalsSet)},
 			{"/" + PostMonitorEvent, jsonContentHdrs, post(m.monitorEvent)},
 			{"/" + GetPostConfig, jsonContentHdrs, post(m.configSet)},
+			{"/" + PostBootstrapNodes, jsonContentHdrs, post(m.bootstrapRegister)},
+			{"/" + PostBootstrapReimage, jsonContentHdrs, post(m.bootstrapReimage)},
+			{"/" + postBootstrapDone, jsonContentHdrs, post(m.bootstrapDone)},
 		},
 	}
 
@@ -129,6 +139,37 @@ func (m *Manager) apiLoop(errCh chan error, servingCh chan struct{}) {
 	}
 }
 
+// httpStatus returns the http status of the response to a request that failed
+// with an error of the code
+func httpStatus(code clustererr.Code) int {
+	switch code {
+	case clustererr.Validation:
+		return http.StatusBadRequest
+	case clustererr.Conflict:
+		return http.StatusConflict
+	case clustererr.Inventory, clustererr.Provisioner, clustererr.Monitor:
+		return http.StatusBadGateway
+	case clustererr.Timeout:
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+// httpError replies to a failed request with the json encoded info of the error
+func httpError(w http.ResponseWriter, err error) {
+	info := clustererr.InfoOf(err)
+	out, merr := json.Marshal(info)
+	if merr != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(httpStatus(info.Code))
+	if _, err := w.Write(out); err != nil {
+		logrus.Errorf("failed to write error response '%s'. Error: %v", out, err)
+	}
+}
+
 type postCallback func(req *APIRequest) error
 
 func post(postCb postCallback) http.HandlerFunc {
@@ -136,14 +177,14 @@ func post(postCb postCallback) http.HandlerFunc {
 		// process data from request body, if any
 		body, err := ioutil.ReadAll(r.Body)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			httpError(w, err)
 			return
 		}
 
 		req := APIRequest{}
 		if len(body) > 0 {
 			if err := json.Unmarshal(body, &req); err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+				httpError(w, errInvalidJSON("request", err))
 				return
 			}
 		}
@@ -156,21 +197,20 @@ func post(postCb postCallback) http.HandlerFunc {
 		if vars["addr"] != "" {
 			req.Addrs = append(req.Addrs, vars["addr"])
 		}
+		if vars["mac"] != "" {
+			req.MAC = vars["mac"]
+		}
 
 		// process query variables
 		req.ExtraVars, err = validateAndSanitizeEmptyExtraVars("extra_vars", req.ExtraVars)
 		if err != nil {
-			http.Error(w,
-				err.Error(),
-				http.StatusInternalServerError)
+			httpError(w, err)
 			return
 		}
 
 		// call the handler
 		if err := postCb(&req); err != nil {
-			http.Error(w,
-				err.Error(),
-				http.StatusInternalServerError)
+			httpError(w, err)
 			return
 		}
 		w.WriteHeader(http.StatusOK)
@@ -211,7 +251,7 @@ func (m *Manager) nodesUpdate(req *APIRequest) error {
 }
 
 func (m *Manager) nodesDiscover(req *APIRequest) error {
-	me := newWaitableEvent(newDiscoverEvent(m, req.Addrs, req.ExtraVars))
+	me := newWaitableEvent(newDiscoverEvent(m, req.Addrs, req.Nodes, req.ExtraVars))
 	m.reqQ <- me
 	return me.waitForCompletion()
 }
@@ -246,6 +286,24 @@ func (m *Manager) monitorEvent(req *APIRequest) error {
 	return nil
 }
 
+func (m *Manager) bootstrapRegister(req *APIRequest) error {
+	me := newWaitableEvent(newBootstrapRegisterEvent(m, req.BootstrapNodes))
+	m.reqQ <- me
+	return me.waitForCompletion()
+}
+
+func (m *Manager) bootstrapReimage(req *APIRequest) error {
+	me := newWaitableEvent(newReimageEvent(m, req.Nodes))
+	m.reqQ <- me
+	return me.waitForCompletion()
+}
+
+func (m *Manager) bootstrapDone(req *APIRequest) error {
+	me := newWaitableEvent(newBootstrapDoneEvent(m, req.MAC))
+	m.reqQ <- me
+	return me.waitForCompletion()
+}
+
 func (m *Manager) configSet(req *APIRequest) error {
 	if req.Config == nil {
 		return errNilConfig()
@@ -264,12 +322,11 @@ func get(getCb getCallback) http.HandlerFunc {
 		req := &APIRequest{
 			Nodes: []string{strings.TrimSpace(vars["tag"])},
 			Job:   strings.TrimSpace(vars["job"]),
+			MAC:   strings.TrimSpace(vars["mac"]),
 		}
 		out, err := getCb(req)
 		if err != nil {
-			http.Error(w,
-				err.Error(),
-				http.StatusInternalServerError)
+			httpError(w, err)
 			return
 		}
 		// can't use a zero value of slice here as the byte Reader returned by
@@ -384,3 +441,43 @@ func (m *Manager) configGet(noop *APIRequest) (io.Reader, error) {
 
 	return bytes.NewReader(out), nil
 }
+
+func (m *Manager) bootstrapScriptGet(req *APIRequest) (io.Reader, error) {
+	if m.bootstrap == nil {
+		return nil, errBootstrapNotConfigured()
+	}
+
+	var out bytes.Buffer
+	if err := m.bootstrap.WriteBootScript(req.MAC, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (m *Manager) bootstrapConfigGet(req *APIRequest) (io.Reader, error) {
+	if m.bootstrap == nil {
+		return nil, errBootstrapNotConfigured()
+	}
+
+	var out bytes.Buffer
+	if err := m.bootstrap.WriteInstallConfig(req.MAC, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (m *Manager) bootstrapNodesGet(noop *APIRequest) (io.Reader, error) {
+	if m.bootstrap == nil {
+		return nil, errBootstrapNotConfigured()
+	}
+
+	nodes := map[string]*bootstrap.Node{}
+	for _, n := range m.bootstrap.GetAllNodes() {
+		nodes[n.MAC] = n
+	}
+	out, err := json.Marshal(nodes)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(out), nil
+}
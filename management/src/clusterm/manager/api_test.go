@@ -2,7 +2,16 @@
 
 package manager
 
-import . "gopkg.in/check.v1"
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/contiv/cluster/management/src/clustererr"
+	"github.com/contiv/errored"
+	. "gopkg.in/check.v1"
+)
 
 type apiSuite struct {
 }
@@ -97,6 +106,25 @@ func (s *apiSuite) TestGetHandlerErrorCase(c *C) {
 			},
 			exptdErr: errJobNotExist("active"),
 		},
+		"bootstrap-script-not-configured": {
+			cb: m.bootstrapScriptGet,
+			arg: &APIRequest{
+				MAC: "52:54:00:ab:cd:ef",
+			},
+			exptdErr: errBootstrapNotConfigured(),
+		},
+		"bootstrap-config-not-configured": {
+			cb: m.bootstrapConfigGet,
+			arg: &APIRequest{
+				MAC: "52:54:00:ab:cd:ef",
+			},
+			exptdErr: errBootstrapNotConfigured(),
+		},
+		"bootstrap-nodes-not-configured": {
+			cb:       m.bootstrapNodesGet,
+			arg:      &APIRequest{},
+			exptdErr: errBootstrapNotConfigured(),
+		},
 	}
 
 	for key, test := range tests {
@@ -105,3 +133,81 @@ func (s *apiSuite) TestGetHandlerErrorCase(c *C) {
 		c.Assert(err.Error(), Equals, test.exptdErr.Error(), Commentf("key: %s", key))
 	}
 }
+
+func (s *apiSuite) TestErrorResponse(c *C) {
+	tests := map[string]struct {
+		err         error
+		exptdStatus int
+		exptdInfo   *clustererr.Info
+	}{
+		"validation": {
+			err:         errInvalidJobLabel("foo"),
+			exptdStatus: http.StatusBadRequest,
+			exptdInfo: &clustererr.Info{
+				Code:    clustererr.Validation,
+				Message: errInvalidJobLabel("foo").Error(),
+			},
+		},
+		"conflict": {
+			err:         errActiveJob("job"),
+			exptdStatus: http.StatusConflict,
+			exptdInfo: &clustererr.Info{
+				Code:    clustererr.Conflict,
+				Message: errActiveJob("job").Error(),
+			},
+		},
+		"inventory-node": {
+			err:         clustererr.Wrap(clustererr.Inventory, "node1", errored.Errorf("refused")),
+			exptdStatus: http.StatusBadGateway,
+			exptdInfo: &clustererr.Info{
+				Code:    clustererr.Inventory,
+				Message: "refused",
+				Node:    "node1",
+			},
+		},
+		"timeout": {
+			err:         clustererr.New(clustererr.Timeout, "timed out"),
+			exptdStatus: http.StatusGatewayTimeout,
+			exptdInfo: &clustererr.Info{
+				Code:    clustererr.Timeout,
+				Message: "timed out",
+			},
+		},
+		"internal": {
+			err:         errored.Errorf("failure"),
+			exptdStatus: http.StatusInternalServerError,
+			exptdInfo: &clustererr.Info{
+				Code:    clustererr.Internal,
+				Message: "failure",
+			},
+		},
+	}
+
+	for key, test := range tests {
+		w := httptest.NewRecorder()
+		httpError(w, test.err)
+		c.Assert(w.Code, Equals, test.exptdStatus, Commentf("key: %s", key))
+		info := &clustererr.Info{}
+		c.Assert(json.Unmarshal(w.Body.Bytes(), info), IsNil, Commentf("key: %s", key))
+		c.Assert(info, DeepEquals, test.exptdInfo, Commentf("key: %s", key))
+	}
+}
+
+func (s *apiSuite) TestCancelNotRunningJob(c *C) {
+	j := NewJob("", func(cancelCh CancelChannel, logs io.Writer) error { return nil }, nil)
+	err := j.Cancel()
+	c.Assert(err, ErrorMatches, "job is not Running")
+	c.Assert(clustererr.CodeOf(err), Equals, clustererr.Conflict)
+
+	w := httptest.NewRecorder()
+	httpError(w, err)
+	c.Assert(w.Code, Equals, http.StatusConflict)
+	info := &clustererr.Info{}
+	c.Assert(json.Unmarshal(w.Body.Bytes(), info), IsNil)
+	c.Assert(info, DeepEquals, &clustererr.Info{Code: clustererr.Conflict, Message: "job is not Running"})
+
+	// the logs of a job that is not running can't be followed either
+	m := Manager{lastJob: j}
+	_, err = m.logsGet(&APIRequest{Job: jobLabelLast})
+	c.Assert(clustererr.CodeOf(err), Equals, clustererr.Conflict)
+}
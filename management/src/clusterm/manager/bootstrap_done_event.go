@@ -0,0 +1,80 @@
+package manager
+
+import (
+	"fmt"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/contiv/cluster/management/src/bootstrap"
+	"github.com/contiv/cluster/management/src/clustererr"
+)
+
+// bootstrapDoneEvent processes the completion of OS install on a bare-metal node
+// and hands it over for discovery.
+type bootstrapDoneEvent struct {
+	mgr *Manager
+	mac string
+}
+
+// newBootstrapDoneEvent creates and returns bootstrapDoneEvent
+func newBootstrapDoneEvent(mgr *Manager, mac string) *bootstrapDoneEvent {
+	return &bootstrapDoneEvent{
+		mgr: mgr,
+		mac: mac,
+	}
+}
+
+func (e *bootstrapDoneEvent) String() string {
+	return fmt.Sprintf("bootstrapDoneEvent: mac: %s", e.mac)
+}
+
+func (e *bootstrapDoneEvent) process() error {
+	if e.mgr.bootstrap == nil {
+		return errBootstrapNotConfigured()
+	}
+
+	n, err := e.mgr.bootstrap.GetNode(e.mac)
+	if err != nil {
+		return err
+	}
+	if n.Status != bootstrap.Install {
+		return clustererr.New(clustererr.Conflict, "node with MAC address %q is not being installed, it's status is %q", n.MAC, n.Status)
+	}
+
+	if err := e.mgr.bootstrap.SetStatus(n.MAC, bootstrap.Installed); err != nil {
+		return err
+	}
+
+	// hand the node over for discovery. The discover event is enqueued
+	// asynchronously as we are running in the event loop
+	logrus.Infof("OS install completed on node %q, provisioning it for discovery", n.Name)
+	go func() {
+		e.mgr.reqQ <- newDiscoverEvent(e.mgr, []string{n.Addr}, nil, n.ExtraVars)
+	}()
+	return nil
+}
+
+// commissionBootstrappedNode commissions a newly discovered node that was bootstrapped
+// by clusterm, if a host-group was specified for it at registration.
+func (m *Manager) commissionBootstrappedNode(name, addr string) {
+	if m.bootstrap == nil {
+		return
+	}
+
+	for _, n := range m.bootstrap.GetAllNodes() {
+		if n.Addr != addr || n.Status != bootstrap.Installed {
+			continue
+		}
+		if err := m.bootstrap.SetStatus(n.MAC, bootstrap.Discovered); err != nil {
+			logrus.Errorf("failed to set bootstrap status of node %q. Error: %v", name, err)
+			return
+		}
+		if n.HostGroup == "" {
+			return
+		}
+		logrus.Infof("bootstrapped node %q is discovered, commissioning it as %q", name, n.HostGroup)
+		go func(n *bootstrap.Node) {
+			m.reqQ <- newCommissionEvent(m, []string{name}, n.ExtraVars, n.HostGroup)
+		}(n)
+		return
+	}
+}
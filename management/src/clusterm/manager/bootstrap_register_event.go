@@ -0,0 +1,75 @@
+package manager
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/contiv/cluster/management/src/bootstrap"
+	"github.com/contiv/cluster/management/src/clustererr"
+)
+
+func errBootstrapNotConfigured() error {
+	return clustererr.New(clustererr.Validation, "bare-metal bootstrap is not configured, please add the bootstrap configuration to clusterm")
+}
+
+// bootstrapRegisterEvent registers bare-metal nodes for bootstrap
+type bootstrapRegisterEvent struct {
+	mgr   *Manager
+	nodes []*bootstrap.Node
+}
+
+// newBootstrapRegisterEvent creates and returns bootstrapRegisterEvent
+func newBootstrapRegisterEvent(mgr *Manager, nodes []*bootstrap.Node) *bootstrapRegisterEvent {
+	return &bootstrapRegisterEvent{
+		mgr:   mgr,
+		nodes: nodes,
+	}
+}
+
+func (e *bootstrapRegisterEvent) String() string {
+	macs := []string{}
+	for _, n := range e.nodes {
+		macs = append(macs, n.MAC)
+	}
+	return fmt.Sprintf("bootstrapRegisterEvent: macs: %v", macs)
+}
+
+func (e *bootstrapRegisterEvent) process() error {
+	if e.mgr.bootstrap == nil {
+		return errBootstrapNotConfigured()
+	}
+
+	if err := e.eventValidate(); err != nil {
+		return err
+	}
+
+	return e.mgr.bootstrap.Register(e.nodes)
+}
+
+func (e *bootstrapRegisterEvent) eventValidate() error {
+	if len(e.nodes) == 0 {
+		return clustererr.New(clustererr.Validation, "atleast one node should be specified")
+	}
+
+	var err error
+	for _, n := range e.nodes {
+		if _, err = bootstrap.NormalizeMAC(n.MAC); err != nil {
+			return err
+		}
+		if n.Name == "" {
+			return clustererr.New(clustererr.Validation, "inventory name needs to be specified for node with MAC address %q", n.MAC)
+		}
+		if ip := net.ParseIP(n.Addr); ip == nil {
+			return clustererr.New(clustererr.Validation, "invalid or empty management address %q specified for node with MAC address %q",
+				n.Addr, n.MAC)
+		}
+		if n.HostGroup != "" && !IsValidHostGroup(n.HostGroup) {
+			return clustererr.New(clustererr.Validation, "invalid host-group %q specified for node with MAC address %q",
+				n.HostGroup, n.MAC)
+		}
+		if n.ExtraVars, err = validateAndSanitizeEmptyExtraVars("extra_vars", n.ExtraVars); err != nil {
+			return err
+		}
+	}
+	return nil
+}
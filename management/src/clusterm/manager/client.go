@@ -8,10 +8,20 @@ import (
 	"io/ioutil"
 	"net/http"
 
+	"github.com/contiv/cluster/management/src/bootstrap"
+	"github.com/contiv/cluster/management/src/clustererr"
 	"github.com/contiv/errored"
 )
 
 var httpErrorResp = func(rsrc string, req *APIRequest, status string, body []byte) error {
+	// the info of the error is returned as is, so that the callers can act on
+	// it's code and nodes
+	info := &clustererr.Info{}
+	if err := json.Unmarshal(body, info); err == nil && info.Code != "" {
+		info.Message = fmt.Sprintf("Request URL: %s Request Body: %+v Response status: %q. Error: %s", rsrc, req,
+			status, info.Message)
+		return info
+	}
 	return errored.Errorf("Request URL: %s Request Body: %+v Response status: %q. Response body: %s", rsrc, req, status, body)
 }
 
@@ -138,7 +148,14 @@ func (c *Client) PostNodesUpdate(nodeNames []string, extraVars, hostGroup string
 
 // PostNodesDiscover posts the request to provision a set of nodes for discovery
 func (c *Client) PostNodesDiscover(nodeAddrs []string, extraVars string) error {
+	return c.PostNodesDiscoverWithPowerOn(nodeAddrs, nil, extraVars)
+}
+
+// PostNodesDiscoverWithPowerOn posts the request to power on a set of inventory
+// assets and then provision them for discovery
+func (c *Client) PostNodesDiscoverWithPowerOn(nodeAddrs, powerOnNodes []string, extraVars string) error {
 	req := &APIRequest{
+		Nodes:     powerOnNodes,
 		Addrs:     nodeAddrs,
 		ExtraVars: extraVars,
 	}
@@ -172,6 +189,27 @@ func (c *Client) PostConfig(config *Config) error {
 	return c.doPost(GetPostConfig, req)
 }
 
+// PostBootstrapNodes posts the request to register a set of bare-metal nodes for bootstrap
+func (c *Client) PostBootstrapNodes(nodes []*bootstrap.Node) error {
+	req := &APIRequest{
+		BootstrapNodes: nodes,
+	}
+	return c.doPost(PostBootstrapNodes, req)
+}
+
+// PostBootstrapReimage posts the request to reimage a set of bare-metal nodes
+func (c *Client) PostBootstrapReimage(nodeNames []string) error {
+	req := &APIRequest{
+		Nodes: nodeNames,
+	}
+	return c.doPost(PostBootstrapReimage, req)
+}
+
+// PostBootstrapDone posts the completion of OS install on a bare-metal node
+func (c *Client) PostBootstrapDone(mac string) error {
+	return c.doPost(fmt.Sprintf("%s/%s", PostBootstrapDonePrefix, mac), &APIRequest{})
+}
+
 func (c *Client) readAll(rsrc string) ([]byte, error) {
 	resp, err := c.doGet(rsrc)
 	if err != nil {
@@ -217,3 +255,8 @@ func (c *Client) GetJob(jobLabel string) ([]byte, error) {
 func (c *Client) StreamLogs(jobLabel string) (io.ReadCloser, error) {
 	return c.doGet(fmt.Sprintf("%s/%s", GetJobLogPrefix, jobLabel))
 }
+
+// GetBootstrapNodes requests info of all bare-metal nodes registered for bootstrap
+func (c *Client) GetBootstrapNodes() ([]byte, error) {
+	return c.readAll(GetBootstrapNodes)
+}
@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/contiv/cluster/management/src/clustererr"
 	"github.com/mapuri/serf/client"
 
 	. "gopkg.in/check.v1"
@@ -381,6 +382,28 @@ func (s *managerSuite) TestPostError(c *C) {
 	c.Assert(err, ErrorMatches, ".*test failure\n")
 }
 
+func (s *managerSuite) TestPostTypedError(c *C) {
+	httpS, httpC := getHTTPTestClientAndServer(c, post(func(req *APIRequest) error {
+		return nodeErrors{
+			"node1": nodeNotDiscoveredError("node1"),
+			"node2": nodeNotDiscoveredError("node2"),
+		}
+	}))
+	defer httpS.Close()
+	clstrC := Client{
+		url:   baseURL,
+		httpC: httpC,
+	}
+	err := clstrC.PostNodesCommission([]string{"node1", "node2"}, "", ansibleMasterGroupName)
+	c.Assert(err, ErrorMatches, `Request URL: .*"409 Conflict". Error: 2 node\(s\) failed.*`)
+	c.Assert(clustererr.CodeOf(err), Equals, clustererr.Conflict)
+	info := clustererr.InfoOf(err)
+	c.Assert(info.Nodes, HasLen, 2)
+	c.Assert(info.Nodes[0].Code, Equals, clustererr.Conflict)
+	c.Assert(info.Nodes[0].Node, Equals, "node1")
+	c.Assert(info.Nodes[1].Node, Equals, "node2")
+}
+
 func (s *managerSuite) TestGetNodeSuccess(c *C) {
 	expURLStr := fmt.Sprintf("http://%s/%s/%s", baseURL, GetNodeInfoPrefix, testNodeName)
 	expURL, err := url.Parse(expURLStr)
@@ -0,0 +1,25 @@
+package manager
+
+import (
+	"github.com/contiv/cluster/management/src/cmdb"
+)
+
+// syncNodeToCMDB syncs the node's info to CMDB, if CMDB sync is configured
+func (m *Manager) syncNodeToCMDB(name string) {
+	if m.cmdb == nil {
+		return
+	}
+	n := &cmdb.Node{Name: name}
+	if enode, err := m.findNode(name); err == nil {
+		if enode.Mon != nil {
+			n.Addr = enode.Mon.GetMgmtAddress()
+			n.Serial = enode.Mon.GetSerial()
+			n.Label = enode.Mon.GetLabel()
+		}
+		if enode.Cfg != nil {
+			n.HostGroup = enode.Cfg.GetGroup()
+		}
+	}
+	n.Status, n.State = m.nodeInventoryStatus(name)
+	m.cmdb.Sync(n)
+}
@@ -5,12 +5,14 @@ import (
 	"io"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/contiv/cluster/management/src/clustererr"
 	"github.com/contiv/cluster/management/src/configuration"
-	"github.com/contiv/errored"
+	"github.com/contiv/cluster/management/src/dns"
+	"github.com/contiv/cluster/management/src/lb"
 )
 
 func errActiveJob(desc string) error {
-	return errored.Errorf("there is already an active job, please try in sometime. Job: %s", desc)
+	return clustererr.New(clustererr.Conflict, "there is already an active job, please try in sometime. Job: %s", desc)
 }
 
 // commissionEvent triggers the commission workflow
@@ -19,9 +21,13 @@ type commissionEvent struct {
 	nodeNames []string
 	extraVars string
 	hostGroup string
+	// commit is the gitops commit that the event applies, if any
+	commit string
 
-	_hosts  configuration.SubsysHosts
-	_enodes map[string]*node
+	_hosts      configuration.SubsysHosts
+	_enodes     map[string]*node
+	_dnsRecords []*dns.Record
+	_lbMembers  []*lb.Member
 }
 
 // newCommissionEvent creates and returns commissionEvent
@@ -45,6 +51,7 @@ func (e *commissionEvent) process() error {
 
 	err = e.mgr.checkAndSetActiveJob(
 		e.String(),
+		jobContext{eventType: "commission", nodes: e.nodeNames, commit: e.commit},
 		e.configureOrCleanupOnErrorRunner,
 		func(status JobStatus, errRet error) {
 			if status == Errored {
@@ -95,42 +102,21 @@ func (e *commissionEvent) eventValidate() error {
 	}
 
 	if !IsValidHostGroup(e.hostGroup) {
-		return errored.Errorf("invalid or empty host-group specified: %q", e.hostGroup)
+		return clustererr.New(clustererr.Validation, "invalid or empty host-group specified: %q", e.hostGroup)
 	}
 
 	// when workers are being configured, make sure that there is atleast one service-master
 	if e.hostGroup == ansibleWorkerGroupName {
 		masterCommissioned := false
-		for name := range e.mgr.nodes {
-			if _, ok := e._enodes[name]; ok {
-				// skip nodes in the event
-				continue
+		for _, name := range e.mgr.discoveredAndAllocatedNodes(ansibleMasterGroupName) {
+			if _, ok := e._enodes[name]; !ok {
+				// found a master node, that is not in the event
+				masterCommissioned = true
+				break
 			}
-
-			isDiscoveredAndAllocated, err := e.mgr.isDiscoveredAndAllocatedNode(name)
-			if err != nil || !isDiscoveredAndAllocated {
-				if err != nil {
-					logrus.Debugf("a node check failed for %q. Error: %s", name, err)
-				}
-				// skip hosts that are not yet provisioned or not in discovered state
-				continue
-			}
-
-			isMasterNode, err := e.mgr.isMasterNode(name)
-			if err != nil || !isMasterNode {
-				if err != nil {
-					logrus.Debugf("a node check failed for %q. Error: %s", name, err)
-				}
-				//skip the hosts that are not in master group
-				continue
-			}
-
-			// found a master node
-			masterCommissioned = true
-			break
 		}
 		if !masterCommissioned {
-			return errored.Errorf("Cannot commission a worker node without existence of a master node in the cluster, make sure atleast one master node is commissioned.")
+			return clustererr.New(clustererr.Validation, "Cannot commission a worker node without existence of a master node in the cluster, make sure atleast one master node is commissioned.")
 		}
 	}
 	return nil
@@ -139,22 +125,32 @@ func (e *commissionEvent) eventValidate() error {
 // prepareInventory adds the specified nodes to the specified host-group
 func (e *commissionEvent) prepareInventory() error {
 	hosts := []*configuration.AnsibleHost{}
-	for _, node := range e._enodes {
+	for name, node := range e._enodes {
 		hostInfo := node.Cfg.(*configuration.AnsibleHost)
 		hostInfo.SetGroup(e.hostGroup)
+		// the host-group index is updated for the new host-group
+		e.mgr.nodes.reindex(name)
 		hosts = append(hosts, hostInfo)
 	}
 	e._hosts = hosts
 
+	// pick the records to be registered in DNS and the load balancer pool
+	// members to be registered, once configured
+	e._dnsRecords = e.mgr.dnsRecords(e._enodes)
+	e._lbMembers = e.mgr.lbMembers(e._enodes)
+
 	return nil
 }
 
 // configureOrCleanupOnErrorRunner is the job runner that runs configuration playbooks on one or more nodes.
+// The nodes are registered in DNS and load balancer pool, if configured, once configuration succeeds.
 // It runs cleanup playbook on failure
 func (e *commissionEvent) configureOrCleanupOnErrorRunner(cancelCh CancelChannel, jobLogs io.Writer) error {
 	outReader, cancelFunc, errCh := e.mgr.configuration.Configure(e._hosts, e.extraVars)
 	cfgErr := logOutputAndReturnStatus(outReader, errCh, cancelCh, cancelFunc, jobLogs)
 	if cfgErr == nil {
+		e.mgr.addDNSRecords(e._dnsRecords, jobLogs)
+		e.mgr.registerLBMembers(e._lbMembers, jobLogs)
 		return nil
 	}
 	logrus.Errorf("configuration failed, starting cleanup. Error: %s", cfgErr)
@@ -6,8 +6,22 @@ import (
 	"io/ioutil"
 
 	"github.com/contiv/cluster/management/src/boltdb"
+	"github.com/contiv/cluster/management/src/bootstrap"
+	"github.com/contiv/cluster/management/src/cmdb"
 	"github.com/contiv/cluster/management/src/collins"
 	"github.com/contiv/cluster/management/src/configuration"
+	"github.com/contiv/cluster/management/src/dns"
+	"github.com/contiv/cluster/management/src/gitops"
+	fakeinv "github.com/contiv/cluster/management/src/inventory/fake"
+	"github.com/contiv/cluster/management/src/lb"
+	"github.com/contiv/cluster/management/src/metrics"
+	"github.com/contiv/cluster/management/src/monitor"
+	"github.com/contiv/cluster/management/src/notify"
+	"github.com/contiv/cluster/management/src/power"
+	"github.com/contiv/cluster/management/src/publisher"
+	"github.com/contiv/cluster/management/src/remotelog"
+	"github.com/contiv/cluster/management/src/retry"
+	"github.com/contiv/cluster/management/src/vault"
 	"github.com/contiv/errored"
 	"github.com/imdario/mergo"
 	"github.com/mapuri/serf/client"
@@ -15,19 +29,133 @@ import (
 
 type clustermConfig struct {
 	Addr string `json:"addr"`
+	// MonitorCoalesceMsecs is the time for which the monitor events are coalesced,
+	// before they are processed as a batch
+	MonitorCoalesceMsecs int `json:"monitor_coalesce_msecs"`
+	// MonitorBatchSize is the count of nodes, upon reaching which the coalesced
+	// monitor events are processed without waiting for the rest of the window
+	MonitorBatchSize int `json:"monitor_batch_size"`
 }
 
 type inventorySubsysConfig struct {
 	Collins *collins.Config `json:"collins,omitempty"`
 	BoltDB  *boltdb.Config  `json:"boltdb,omitempty"`
+	// Fake is the in-memory inventory, to simulate a cluster
+	Fake *fakeinv.Config `json:"fake,omitempty"`
+	// Retry is the retry policy of the inventory updates that fail due to the backend
+	Retry retry.Policy `json:"retry"`
+}
+
+type monitorSubsysConfig struct {
+	// Fake is the monitoring subsystem that doesn't watch real nodes, to
+	// simulate a cluster. Serf is used when it is not set
+	Fake *monitor.FakeConfig `json:"fake,omitempty"`
+	// Retry is the retry policy of serf's monitor loop
+	Retry retry.Policy `json:"retry"`
+}
+
+type configurationSubsysConfig struct {
+	// Fake is the configuration subsystem that simulates the actions on the
+	// nodes, to simulate a cluster. Ansible is used when it is not set
+	Fake *configuration.FakeSubsysConfig `json:"fake,omitempty"`
+}
+
+type powerSubsysConfig struct {
+	IPMI    *power.IPMIConfig    `json:"ipmi,omitempty"`
+	Redfish *power.RedfishConfig `json:"redfish,omitempty"`
+	// BootWaitSecs is the time to wait for the nodes to boot after they are powered on
+	BootWaitSecs int `json:"boot_wait_secs"`
+}
+
+type dnsSubsysConfig struct {
+	Route53 *dns.Route53Config `json:"route53,omitempty"`
+	Bind    *dns.BindConfig    `json:"bind,omitempty"`
+	CoreDNS *dns.CoreDNSConfig `json:"coredns,omitempty"`
+	// RecordName is the go template for the DNS record name of a node. It is
+	// evaluated with the node's Name, Addr and HostGroup
+	RecordName string `json:"record_name"`
+	// HostGroupRecordNames overrides the RecordName template for specific host-groups
+	HostGroupRecordNames map[string]string `json:"host_group_record_names,omitempty"`
+	TTL                  int               `json:"ttl"`
+}
+
+type loadBalancerSubsysConfig struct {
+	HAProxy        *lb.HAProxyConfig     `json:"haproxy,omitempty"`
+	AWSTargetGroup *lb.TargetGroupConfig `json:"aws_target_group,omitempty"`
+	// HostGroups are the host-groups whose nodes are added to the pool. Nodes
+	// in all host-groups are added when it is empty
+	HostGroups []string `json:"host_groups,omitempty"`
+	// DrainWaitSecs is the time to wait for connections to drain before a node is cleaned up
+	DrainWaitSecs int `json:"drain_wait_secs"`
+}
+
+type notificationSubsysConfig struct {
+	Channels []notify.ChannelConfig `json:"channels,omitempty"`
+	// Templates overrides the default message templates for the event types
+	Templates map[notify.EventType]string `json:"templates,omitempty"`
+	// NodeDownThresholdSecs is the time a commissioned node needs to be down before it is notified
+	NodeDownThresholdSecs int `json:"node_down_threshold_secs"`
+}
+
+type eventPublisherSubsysConfig struct {
+	NATS  *publisher.NATSConfig  `json:"nats,omitempty"`
+	Kafka *publisher.KafkaConfig `json:"kafka,omitempty"`
+}
+
+type cmdbSubsysConfig struct {
+	ServiceNow *cmdb.ServiceNowConfig `json:"servicenow,omitempty"`
+	REST       *cmdb.RESTConfig       `json:"rest,omitempty"`
+	// FieldMap maps the CMDB fields to the node fields. The driver's default mapping
+	// is used when it is empty
+	FieldMap cmdb.FieldMap    `json:"field_map,omitempty"`
+	Retry    cmdb.RetryConfig `json:"retry"`
+}
+
+type remoteLoggingSubsysConfig struct {
+	Syslog  *remotelog.SyslogConfig  `json:"syslog,omitempty"`
+	Fluentd *remotelog.FluentdConfig `json:"fluentd,omitempty"`
+}
+
+type metricsSubsysConfig struct {
+	StatsD   *metrics.StatsDConfig   `json:"statsd,omitempty"`
+	Graphite *metrics.GraphiteConfig `json:"graphite,omitempty"`
+	// IntervalSecs is the interval at which the queue depth and node count gauges are emitted
+	IntervalSecs int `json:"interval_secs"`
+}
+
+type jobLogsConfig struct {
+	// Dir is the directory where the job logs are written. The logs are only
+	// tailed in memory when it is empty
+	Dir string `json:"dir"`
+	// MaxFileSizeMB is the cap on the size of a job's log file. The logs after it
+	// is reached are only tailed in memory
+	MaxFileSizeMB int `json:"max_file_size_mb"`
+	// MaxFiles is the count of the latest job log files that are retained
+	MaxFiles int `json:"max_files"`
+	// TailSizeKB is the size of the last logs of a job that are kept in memory
+	TailSizeKB int `json:"tail_size_kb"`
 }
 
 // Config is the configuration to cluster manager daemon
 type Config struct {
-	Serf      client.Config                     `json:"serf"`
-	Inventory inventorySubsysConfig             `json:"inventory"`
-	Ansible   configuration.AnsibleSubsysConfig `json:"ansible"`
-	Manager   clustermConfig                    `json:"manager"`
+	Serf          client.Config                     `json:"serf"`
+	Inventory     inventorySubsysConfig             `json:"inventory"`
+	Ansible       configuration.AnsibleSubsysConfig `json:"ansible"`
+	Manager       clustermConfig                    `json:"manager"`
+	Power         powerSubsysConfig                 `json:"power"`
+	Bootstrap     *bootstrap.Config                 `json:"bootstrap,omitempty"`
+	DNS           dnsSubsysConfig                   `json:"dns"`
+	LoadBalancer  loadBalancerSubsysConfig          `json:"loadbalancer"`
+	Notifications notificationSubsysConfig          `json:"notifications"`
+	Events        eventPublisherSubsysConfig        `json:"events"`
+	Vault         *vault.Config                     `json:"vault,omitempty"`
+	CMDB          cmdbSubsysConfig                  `json:"cmdb"`
+	RemoteLogging remoteLoggingSubsysConfig         `json:"remote_logging"`
+	Metrics       metricsSubsysConfig               `json:"metrics"`
+	GitOps        *gitops.Config                    `json:"gitops,omitempty"`
+	JobLogs       jobLogsConfig                     `json:"job_logs"`
+	Monitor       monitorSubsysConfig               `json:"monitor"`
+	Configuration configurationSubsysConfig         `json:"configuration"`
 }
 
 // DefaultConfig returns the default configuration values for the cluster manager
@@ -40,6 +168,13 @@ func DefaultConfig() *Config {
 		Inventory: inventorySubsysConfig{
 			BoltDB:  nil,
 			Collins: nil,
+			Fake:    nil,
+			Retry: retry.Policy{
+				MaxAttempts:         3,
+				InitialBackoffMsecs: 200,
+				MaxBackoffMsecs:     2000,
+				Jitter:              0.2,
+			},
 		},
 		Ansible: configuration.AnsibleSubsysConfig{
 			ConfigurePlaybook: "site.yml",
@@ -50,7 +185,68 @@ func DefaultConfig() *Config {
 			PrivKeyFile:       "/vagrant/management/src/demo/files/insecure_private_key",
 		},
 		Manager: clustermConfig{
-			Addr: "0.0.0.0:9007",
+			Addr:                 "0.0.0.0:9007",
+			MonitorCoalesceMsecs: 500,
+			MonitorBatchSize:     256,
+		},
+		Power: powerSubsysConfig{
+			IPMI:         nil,
+			Redfish:      nil,
+			BootWaitSecs: 120,
+		},
+		Bootstrap: nil,
+		DNS: dnsSubsysConfig{
+			Route53:    nil,
+			Bind:       nil,
+			CoreDNS:    nil,
+			RecordName: "{{.Name}}.cluster.local",
+			TTL:        300,
+		},
+		LoadBalancer: loadBalancerSubsysConfig{
+			HAProxy:        nil,
+			AWSTargetGroup: nil,
+			DrainWaitSecs:  30,
+		},
+		Notifications: notificationSubsysConfig{
+			NodeDownThresholdSecs: 300,
+		},
+		Events: eventPublisherSubsysConfig{
+			NATS:  nil,
+			Kafka: nil,
+		},
+		Vault: nil,
+		CMDB: cmdbSubsysConfig{
+			ServiceNow: nil,
+			REST:       nil,
+			Retry:      cmdb.DefaultRetryConfig(),
+		},
+		RemoteLogging: remoteLoggingSubsysConfig{
+			Syslog:  nil,
+			Fluentd: nil,
+		},
+		Metrics: metricsSubsysConfig{
+			StatsD:       nil,
+			Graphite:     nil,
+			IntervalSecs: 10,
+		},
+		GitOps: nil,
+		JobLogs: jobLogsConfig{
+			Dir:           "/var/log/clusterm/jobs",
+			MaxFileSizeMB: 100,
+			MaxFiles:      20,
+			TailSizeKB:    256,
+		},
+		Monitor: monitorSubsysConfig{
+			Fake: nil,
+			Retry: retry.Policy{
+				MaxAttempts:         0,
+				InitialBackoffMsecs: 1000,
+				MaxBackoffMsecs:     60000,
+				Jitter:              0.2,
+			},
+		},
+		Configuration: configurationSubsysConfig{
+			Fake: nil,
 		},
 	}
 }
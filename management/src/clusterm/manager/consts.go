@@ -2,6 +2,8 @@
 
 package manager
 
+import "github.com/contiv/cluster/management/src/bootstrap"
+
 const (
 	// PostNodesCommission is the prefix for the POST REST endpoint
 	// to commission one or more assets
@@ -27,6 +29,34 @@ const (
 	// to post a monitor event for one or more nodes.
 	PostMonitorEvent = "monitor/event"
 
+	// PostBootstrapNodes is the prefix for the POST REST endpoint
+	// to register one or more bare-metal nodes for bootstrap
+	PostBootstrapNodes = "bootstrap/nodes"
+
+	// PostBootstrapReimage is the prefix for the POST REST endpoint
+	// to reimage one or more bare-metal nodes registered for bootstrap
+	PostBootstrapReimage = "bootstrap/reimage"
+
+	// PostBootstrapDonePrefix is the prefix for the POST REST endpoint
+	// to signal the completion of OS install on a bare-metal node. This is
+	// usually posted by the OS installer at the end of install.
+	PostBootstrapDonePrefix = bootstrap.DoneURLPrefix
+	postBootstrapDone       = PostBootstrapDonePrefix + "/{mac}"
+
+	// GetBootstrapScriptPrefix is the prefix for the GET REST endpoint
+	// to fetch the iPXE boot script of a bare-metal node
+	GetBootstrapScriptPrefix = "bootstrap/ipxe"
+	getBootstrapScript       = GetBootstrapScriptPrefix + "/{mac}"
+
+	// GetBootstrapConfigPrefix is the prefix for the GET REST endpoint
+	// to fetch the OS install configuration (kickstart or preseed) of a bare-metal node
+	GetBootstrapConfigPrefix = bootstrap.ConfigURLPrefix
+	getBootstrapConfig       = GetBootstrapConfigPrefix + "/{mac}"
+
+	// GetBootstrapNodes is the prefix for the GET REST endpoint
+	// to fetch info for all bare-metal nodes registered for bootstrap
+	GetBootstrapNodes = "info/bootstrap"
+
 	// GetNodeInfoPrefix is the prefix for the GET REST endpoint
 	// to fetch info for an asset
 	GetNodeInfoPrefix = "info/node"
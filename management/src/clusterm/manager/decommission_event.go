@@ -5,8 +5,11 @@ import (
 	"io"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/contiv/cluster/management/src/clustererr"
 	"github.com/contiv/cluster/management/src/configuration"
-	"github.com/contiv/errored"
+	"github.com/contiv/cluster/management/src/dns"
+	"github.com/contiv/cluster/management/src/lb"
+	"github.com/contiv/cluster/management/src/power"
 )
 
 // decommissionEvent triggers the decommission workflow
@@ -14,9 +17,14 @@ type decommissionEvent struct {
 	mgr       *Manager
 	nodeNames []string
 	extraVars string
-
-	_hosts  configuration.SubsysHosts
-	_enodes map[string]*node
+	// commit is the gitops commit that the event applies, if any
+	commit string
+
+	_hosts      configuration.SubsysHosts
+	_enodes     map[string]*node
+	_bmcs       map[string]*power.BMC
+	_dnsRecords []*dns.Record
+	_lbMembers  []*lb.Member
 }
 
 // newDecommissionEvent creates and returns decommissionEvent
@@ -38,6 +46,7 @@ func (e *decommissionEvent) process() error {
 
 	err = e.mgr.checkAndSetActiveJob(
 		e.String(),
+		jobContext{eventType: "decommission", nodes: e.nodeNames, commit: e.commit},
 		e.cleanupRunner,
 		func(status JobStatus, errRet error) {
 			if status == Errored {
@@ -85,34 +94,19 @@ func (e *decommissionEvent) process() error {
 func (e *decommissionEvent) prepareInventory() error {
 	mastersLeft := 0
 	workersLeft := 0
-	for name := range e.mgr.nodes {
-		if _, ok := e._enodes[name]; ok {
-			// skip the node in the event
-			continue
-		}
-		isDiscoveredAndAllocated, err := e.mgr.isDiscoveredAndAllocatedNode(name)
-		if err != nil || !isDiscoveredAndAllocated {
-			if err != nil {
-				logrus.Debugf("a node check failed for %q. Error: %s", name, err)
-			}
-			// skip hosts that are not yet provisioned or not in discovered state
-			continue
-		}
-		isWorkerNode, err := e.mgr.isWorkerNode(name)
-		if err != nil {
-			// skip this node
-			logrus.Debugf("a node check failed for %q. Error: %s", name, err)
-			continue
+	for _, name := range e.mgr.discoveredAndAllocatedNodes(ansibleMasterGroupName) {
+		if _, ok := e._enodes[name]; !ok {
+			mastersLeft++
 		}
-		if isWorkerNode {
+	}
+	for _, name := range e.mgr.discoveredAndAllocatedNodes(ansibleWorkerGroupName) {
+		if _, ok := e._enodes[name]; !ok {
 			workersLeft++
-		} else {
-			mastersLeft++
 		}
 	}
 
 	if workersLeft > 0 && mastersLeft <= 0 {
-		return errored.Errorf("decommissioning the specified node(s) will leave only worker nodes in the cluster, make sure all worker nodes are decommissioned before last master node.")
+		return clustererr.New(clustererr.Validation, "decommissioning the specified node(s) will leave only worker nodes in the cluster, make sure all worker nodes are decommissioned before last master node.")
 	}
 
 	// prepare the inventory
@@ -122,14 +116,41 @@ func (e *decommissionEvent) prepareInventory() error {
 	}
 	e._hosts = hosts
 
+	// pick the nodes to be powered off once cleaned up
+	e._bmcs = e.mgr.powerableNodesBMC(e.nodeNames)
+
+	// pick the records to be removed from DNS and the members to be drained
+	// from load balancer pool
+	e._dnsRecords = e.mgr.dnsRecords(e._enodes)
+	e._lbMembers = e.mgr.lbMembers(e._enodes)
+
 	return nil
 }
 
-// cleanupRunner is the job runner that runs cleanup playbooks on one or more nodes
+// cleanupRunner is the job runner that runs cleanup playbooks on one or more nodes.
+// The nodes are removed from DNS and drained from load balancer pool before cleanup.
+// They are removed from the pool and powered off after cleanup. Each of these
+// steps is done only if the respective subsystem is configured.
 func (e *decommissionEvent) cleanupRunner(cancelCh CancelChannel, jobLogs io.Writer) error {
+	e.mgr.removeDNSRecords(e._dnsRecords, jobLogs)
+	if err := e.mgr.drainLBMembers(e._lbMembers, cancelCh, jobLogs); err != nil {
+		e.mgr.deregisterLBMembers(e._lbMembers, jobLogs)
+		return err
+	}
 	outReader, cancelFunc, errCh := e.mgr.configuration.Cleanup(e._hosts, e.extraVars)
-	if err := logOutputAndReturnStatus(outReader, errCh, cancelCh, cancelFunc, jobLogs); err != nil {
+	err := logOutputAndReturnStatus(outReader, errCh, cancelCh, cancelFunc, jobLogs)
+	// the nodes are marked decommissioned irrespective of the cleanup status,
+	// so they are removed from the pool in either case
+	e.mgr.deregisterLBMembers(e._lbMembers, jobLogs)
+	if err != nil {
 		return err
 	}
+	if len(e._bmcs) > 0 {
+		// the nodes have been cleaned up at this point, so a power off failure
+		// is logged but doesn't fail the decommission
+		if err := powerNodes(e._bmcs, "off", e.mgr.power.PowerOff, jobLogs); err != nil {
+			logrus.Errorf("power off after cleanup failed. Error: %s", err)
+		}
+	}
 	return nil
 }
@@ -3,7 +3,9 @@ package manager
 import (
 	"fmt"
 
+	"github.com/Sirupsen/logrus"
 	"github.com/contiv/cluster/management/src/monitor"
+	"github.com/contiv/cluster/management/src/publisher"
 )
 
 // disappearedEvent processes the disappeared event from monitoring subsystem
@@ -21,24 +23,50 @@ func newDisappearedEvent(mgr *Manager, nodes []monitor.SubsysNode) *disappearedE
 }
 
 func (e *disappearedEvent) String() string {
-	return fmt.Sprintf("disappearedEvent: %+v", e.nodes[0])
+	return fmt.Sprintf("disappearedEvent: %v", monitorNodeNames(e.nodes))
 }
 
 func (e *disappearedEvent) process() error {
-	//XXX: need to form the name that adheres to collins tag requirements
-	name := e.nodes[0].GetLabel() + "-" + e.nodes[0].GetSerial()
+	errs := nodeErrors{}
+	names := []string{}
+	for _, mon := range e.nodes {
+		name := monitorNodeName(mon)
+		node, err := e.mgr.findNode(name)
+		if err != nil {
+			errs[name] = err
+			continue
+		}
 
-	node, err := e.mgr.findNode(name)
-	if err != nil {
-		return err
+		// update node's monitoring info to the one received in the event.
+		node.Mon = mon
+		names = append(names, name)
 	}
 
-	// update node's monitoring info to the one received in the event.
-	node.Mon = e.nodes[0]
+	// the inventory state of the nodes is set in a single batch
+	if len(names) > 0 {
+		err := e.mgr.retryInventory(fmt.Sprintf("setting %d asset(s) to disappeared in inventory", len(names)),
+			nil, e.mgr.eventLoopRetryPolicy(),
+			func() error { return e.mgr.inventory.SetAssetsDisappeared(names) })
+		if err != nil {
+			// XXX. Log this to collins
+			logrus.Errorf("setting assets to disappeared in inventory failed. Error: %s", err)
+		}
+		disappeared := splitBatchErrors(names, err, errs)
+		for name := range errs {
+			e.mgr.nodes.reindex(name)
+		}
+		for _, name := range disappeared {
+			e.mgr.nodeChanged(publisher.NodeDisappeared, name)
 
-	if err := e.mgr.inventory.SetAssetDisappeared(name); err != nil {
-		// XXX. Log this to collins
-		return err
+			// notify if the node stays down or it's disappearance puts quorum at risk
+			node, _ := e.mgr.findNode(name)
+			e.mgr.watchNodeDown(name, node.Mon)
+			e.mgr.checkQuorumRisk(name)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
 	}
 	return nil
 }
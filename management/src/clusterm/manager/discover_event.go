@@ -3,32 +3,40 @@ package manager
 import (
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/contiv/cluster/management/src/clustererr"
 	"github.com/contiv/cluster/management/src/configuration"
-	"github.com/contiv/errored"
+	"github.com/contiv/cluster/management/src/power"
 )
 
 // discoverEvent triggers the node discovery workflow
 type discoverEvent struct {
-	mgr       *Manager
-	nodeAddrs []string
-	extraVars string
+	mgr          *Manager
+	nodeAddrs    []string
+	powerOnNodes []string
+	extraVars    string
 
-	_hosts configuration.SubsysHosts
+	_hosts    configuration.SubsysHosts
+	_bmcs     map[string]*power.BMC
+	_bootWait time.Duration
 }
 
-// newDiscoverEvent creates and returns discoverEvent
-func newDiscoverEvent(mgr *Manager, nodeAddrs []string, extraVars string) *discoverEvent {
+// newDiscoverEvent creates and returns discoverEvent. The powerOnNodes are the
+// names of inventory assets that are powered on before they are provisioned for discovery.
+func newDiscoverEvent(mgr *Manager, nodeAddrs, powerOnNodes []string, extraVars string) *discoverEvent {
 	return &discoverEvent{
-		mgr:       mgr,
-		nodeAddrs: nodeAddrs,
-		extraVars: extraVars,
+		mgr:          mgr,
+		nodeAddrs:    nodeAddrs,
+		powerOnNodes: powerOnNodes,
+		extraVars:    extraVars,
 	}
 }
 
 func (e *discoverEvent) String() string {
-	return fmt.Sprintf("discoverEvent: addr: %v extra-vars: %v", e.nodeAddrs, e.extraVars)
+	return fmt.Sprintf("discoverEvent: addr: %v power-on: %v extra-vars: %v", e.nodeAddrs,
+		e.powerOnNodes, e.extraVars)
 }
 
 func (e *discoverEvent) process() error {
@@ -37,6 +45,8 @@ func (e *discoverEvent) process() error {
 
 	err = e.mgr.checkAndSetActiveJob(
 		e.String(),
+		// the nodes are not known by name until they are discovered
+		jobContext{eventType: "discover", nodes: e.nodeAddrs},
 		e.discoverRunner,
 		func(status JobStatus, errRet error) {
 			if status == Errored {
@@ -61,10 +71,17 @@ func (e *discoverEvent) process() error {
 		}
 	}
 	if len(existingNodes) > 0 {
-		err = errored.Errorf("one or more nodes already exist with the specified management addresses. Existing nodes: %v", existingNodes)
+		err = clustererr.New(clustererr.Conflict, "one or more nodes already exist with the specified management addresses. Existing nodes: %v", existingNodes)
 		return err
 	}
 
+	if len(e.powerOnNodes) > 0 {
+		if e._bmcs, err = e.mgr.nodesBMC(e.powerOnNodes); err != nil {
+			return err
+		}
+		e._bootWait = time.Duration(e.mgr.config.Power.BootWaitSecs) * time.Second
+	}
+
 	// prepare inventory
 	if err = e.pepareInventory(); err != nil {
 		return err
@@ -94,8 +111,18 @@ func (e *discoverEvent) pepareInventory() error {
 }
 
 // discoverRunner is the job runner that runs configuration plabooks on one or more nodes
-// It adds the node(s) to contiv-node hostgroup
+// It adds the node(s) to contiv-node hostgroup. If requested, the nodes are powered on
+// before running the playbooks.
 func (e *discoverEvent) discoverRunner(cancelCh CancelChannel, jobLogs io.Writer) error {
+	if len(e._bmcs) > 0 {
+		if err := powerNodes(e._bmcs, "on", e.mgr.power.PowerOn, jobLogs); err != nil {
+			logrus.Errorf("discover failed. Error: %s", err)
+			return err
+		}
+		if err := waitForBoot(e._bootWait, cancelCh, jobLogs); err != nil {
+			return err
+		}
+	}
 	outReader, cancelFunc, errCh := e.mgr.configuration.Configure(e._hosts, e.extraVars)
 	if err := logOutputAndReturnStatus(outReader, errCh, cancelCh, cancelFunc, jobLogs); err != nil {
 		logrus.Errorf("discover failed. Error: %s", err)
@@ -6,6 +6,7 @@ import (
 	"github.com/Sirupsen/logrus"
 	"github.com/contiv/cluster/management/src/configuration"
 	"github.com/contiv/cluster/management/src/monitor"
+	"github.com/contiv/cluster/management/src/publisher"
 )
 
 // discoveredEvent processes the discovered event from monitoring subsystem
@@ -23,43 +24,78 @@ func newDiscoveredEvent(mgr *Manager, nodes []monitor.SubsysNode) *discoveredEve
 }
 
 func (e *discoveredEvent) String() string {
-	return fmt.Sprintf("discoveredEvent: %+v", e.nodes[0])
+	return fmt.Sprintf("discoveredEvent: %v", monitorNodeNames(e.nodes))
 }
 
 func (e *discoveredEvent) process() error {
-	//XXX: need to form the name that adheres to collins tag requirements
-	name := e.nodes[0].GetLabel() + "-" + e.nodes[0].GetSerial()
-
-	enode, err := e.mgr.findNode(name)
-	if err != nil && err.Error() == nodeNotExistsError(name).Error() {
-		e.mgr.nodes[name] = &node{
-			// XXX: node's role/group shall come from manager's role assignment logic or
-			// from user configuration
-			Cfg: configuration.NewAnsibleHost(name, e.nodes[0].GetMgmtAddress(),
-				ansibleMasterGroupName, map[string]string{
-					ansibleNodeNameHostVar: name,
-					ansibleNodeAddrHostVar: e.nodes[0].GetMgmtAddress(),
-				}),
+	errs := nodeErrors{}
+	// the nodes that get added to inventory and the ones that already exist
+	added := []string{}
+	existing := []string{}
+	for _, mon := range e.nodes {
+		name := monitorNodeName(mon)
+		enode, err := e.mgr.findNode(name)
+		if err != nil && err.Error() == nodeNotExistsError(name).Error() {
+			enode = &node{
+				// XXX: node's role/group shall come from manager's role assignment logic or
+				// from user configuration
+				Cfg: configuration.NewAnsibleHost(name, mon.GetMgmtAddress(),
+					ansibleMasterGroupName, map[string]string{
+						ansibleNodeNameHostVar: name,
+						ansibleNodeAddrHostVar: mon.GetMgmtAddress(),
+					}),
+			}
+			e.mgr.nodes.add(name, enode)
+		} else if err != nil {
+			errs[name] = err
+			continue
 		}
-		enode = e.mgr.nodes[name]
-	} else if err != nil {
-		return err
-	}
 
-	// update node's monitoring info to the one received in the event
-	enode.Mon = e.nodes[0]
-	enode.Inv = e.mgr.inventory.GetAsset(name)
-	if enode.Inv == nil {
+		// update node's monitoring info to the one received in the event
+		enode.Mon = mon
+		enode.Inv = e.mgr.inventory.GetAsset(name)
+		if enode.Inv != nil {
+			existing = append(existing, name)
+			continue
+		}
 		if err := e.mgr.inventory.AddAsset(name); err != nil {
 			// XXX. Log this to collins
 			logrus.Errorf("adding asset %q to discovered in inventory failed. Error: %s", name, err)
-			return err
+			errs[name] = err
+			e.mgr.nodes.reindex(name)
+			continue
 		}
 		enode.Inv = e.mgr.inventory.GetAsset(name)
-	} else if err := e.mgr.inventory.SetAssetDiscovered(name); err != nil {
-		// XXX. Log this to collins
-		logrus.Errorf("setting asset %q to discovered in inventory failed. Error: %s", name, err)
-		return err
+		added = append(added, name)
+	}
+
+	// the inventory state of the existing nodes is set in a single batch
+	discovered := added
+	if len(existing) > 0 {
+		err := e.mgr.retryInventory(fmt.Sprintf("setting %d asset(s) to discovered in inventory", len(existing)),
+			nil, e.mgr.eventLoopRetryPolicy(),
+			func() error { return e.mgr.inventory.SetAssetsDiscovered(existing) })
+		if err != nil {
+			// XXX. Log this to collins
+			logrus.Errorf("setting assets to discovered in inventory failed. Error: %s", err)
+		}
+		discovered = append(discovered, splitBatchErrors(existing, err, errs)...)
+		for name := range errs {
+			e.mgr.nodes.reindex(name)
+		}
+	}
+
+	for _, name := range discovered {
+		e.mgr.nodeChanged(publisher.NodeDiscovered, name)
+
+		// commission the node if it was bootstrapped by us and a host-group was requested
+		if enode, err := e.mgr.findNode(name); err == nil {
+			e.mgr.commissionBootstrappedNode(name, enode.Mon.GetMgmtAddress())
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
 	}
 	return nil
 }
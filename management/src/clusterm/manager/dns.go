@@ -0,0 +1,86 @@
+package manager
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/contiv/cluster/management/src/dns"
+)
+
+// dnsRecords returns the DNS records of the specified nodes, as named by the
+// record name templates for their host-group. It returns nil if DNS registration
+// is not configured. The nodes whose record can't be formed are skipped.
+func (m *Manager) dnsRecords(enodes map[string]*node) []*dns.Record {
+	if m.dns == nil {
+		return nil
+	}
+	records := []*dns.Record{}
+	for name, node := range enodes {
+		if node.Mon == nil || node.Cfg == nil {
+			logrus.Infof("node %q shall not be registered in DNS as it's monitoring or configuration info is missing", name)
+			continue
+		}
+		host := &dns.RecordHost{
+			Name:      name,
+			Addr:      node.Mon.GetMgmtAddress(),
+			HostGroup: node.Cfg.GetGroup(),
+		}
+		recordName, err := m.dnsNamer.Name(host)
+		if err != nil {
+			logrus.Errorf("node %q shall not be registered in DNS. Error: %v", name, err)
+			continue
+		}
+		records = append(records, &dns.Record{
+			Name: recordName,
+			Addr: host.Addr,
+			TTL:  m.config.DNS.TTL,
+		})
+	}
+	return records
+}
+
+// staleDNSRecords returns the records in oldRecords whose name is not in newRecords
+func staleDNSRecords(oldRecords, newRecords []*dns.Record) []*dns.Record {
+	names := map[string]struct{}{}
+	for _, r := range newRecords {
+		names[r.Name] = struct{}{}
+	}
+	stale := []*dns.Record{}
+	for _, r := range oldRecords {
+		if _, ok := names[r.Name]; !ok {
+			stale = append(stale, r)
+		}
+	}
+	return stale
+}
+
+type dnsCallback func(r *dns.Record) error
+
+// updateDNSRecords runs the DNS action on the specified records. DNS registration
+// is done on a best effort basis, so the failures are only logged.
+func updateDNSRecords(records []*dns.Record, action string, dnsCb dnsCallback, jobLogs io.Writer) {
+	for _, r := range records {
+		fmt.Fprintf(jobLogs, "%s DNS record %s\n", action, r)
+		if err := dnsCb(r); err != nil {
+			logrus.Errorf("failed to %s DNS record %s. Error: %v", action, r, err)
+			fmt.Fprintf(jobLogs, "failed to %s DNS record %s. Error: %v\n", action, r, err)
+		}
+	}
+}
+
+// addDNSRecords creates the specified records in DNS, if DNS registration is configured
+func (m *Manager) addDNSRecords(records []*dns.Record, jobLogs io.Writer) {
+	if m.dns == nil {
+		return
+	}
+	updateDNSRecords(records, "add", m.dns.AddRecord, jobLogs)
+}
+
+// removeDNSRecords removes the specified records from DNS, if DNS registration is configured
+func (m *Manager) removeDNSRecords(records []*dns.Record, jobLogs io.Writer) {
+	if m.dns == nil {
+		return
+	}
+	updateDNSRecords(records, "remove", m.dns.RemoveRecord, jobLogs)
+}
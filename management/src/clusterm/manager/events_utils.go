@@ -2,16 +2,20 @@ package manager
 
 import (
 	"bufio"
+	"fmt"
 	"io"
+	"sort"
+	"strings"
 	"time"
 
 	"golang.org/x/net/context"
 
 	"github.com/Sirupsen/logrus"
-	"github.com/contiv/errored"
+	"github.com/contiv/cluster/management/src/clustererr"
+	"github.com/contiv/cluster/management/src/inventory"
 )
 
-var errJobCancelled = errored.Errorf("job was cancelled")
+var errJobCancelled = clustererr.New(clustererr.Conflict, "job was cancelled")
 
 // helper function to log the stream of bytes from a reader while waiting on
 // the error channel. It returns on first error received on the channel
@@ -20,7 +24,7 @@ func logOutputAndReturnStatus(r io.Reader, errCh chan error, cancelCh CancelChan
 	// this can happen if an error occurred before the ansible could be run,
 	// just return that error
 	if r == nil {
-		return <-errCh
+		return clustererr.Wrap(clustererr.Provisioner, "", <-errCh)
 	}
 
 	// redirect read output to job logs
@@ -41,7 +45,7 @@ func logOutputAndReturnStatus(r io.Reader, errCh chan error, cancelCh CancelChan
 			for s.Scan() {
 				logrus.Infof("%s", s.Bytes())
 			}
-			return err
+			return clustererr.Wrap(clustererr.Provisioner, "", err)
 		case <-ticker:
 			// scan any available output while waiting
 			if s.Scan() {
@@ -52,28 +56,69 @@ func logOutputAndReturnStatus(r io.Reader, errCh chan error, cancelCh CancelChan
 }
 
 // commonEventValidate does common validation for events. It returns a map of nodes
-// associted with their name on success
+// associted with their name on success. The errors of all the nodes that fail
+// validation are returned.
 func (m *Manager) commonEventValidate(nodeNames []string) (map[string]*node, error) {
 	if len(nodeNames) == 0 {
-		return nil, errored.Errorf("atleast one node should be specified")
-	}
-
-	err := m.areDiscoveredNodes(nodeNames)
-	if err != nil {
-		return nil, err
+		return nil, clustererr.New(clustererr.Validation, "atleast one node should be specified")
 	}
 
 	enodes := map[string]*node{}
-	for _, name := range nodeNames {
+	if err := m.forEachNode(nodeNames, func(name string) error {
 		node, err := m.findNode(name)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		if node.Inv == nil {
+			return nodeInventoryNotExistsError(name)
+		}
+		if _, state := node.Inv.GetStatus(); state != inventory.Discovered {
+			return nodeNotDiscoveredError(name)
 		}
 		if node.Cfg == nil {
-			return nil, nodeConfigNotExistsError(name)
+			return nodeConfigNotExistsError(name)
 		}
 		enodes[name] = node
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 
 	return enodes, nil
 }
+
+// nodeErrors aggregates the errors of the nodes, associated with their name
+type nodeErrors map[string]error
+
+func (ne nodeErrors) Error() string {
+	names := []string{}
+	for name := range ne {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	errs := []string{}
+	for _, name := range names {
+		errs = append(errs, fmt.Sprintf("%s: %v", name, ne[name]))
+	}
+	return fmt.Sprintf("%d node(s) failed. Errors: [%s]", len(ne), strings.Join(errs, "; "))
+}
+
+// Errors returns the errors keyed by node name
+func (ne nodeErrors) Errors() map[string]error {
+	return ne
+}
+
+// forEachNode calls the function for every node, continuing on failures. It returns
+// nodeErrors with the error of every node that the function fails for.
+func (m *Manager) forEachNode(nodeNames []string, fn func(name string) error) error {
+	errs := nodeErrors{}
+	for _, name := range nodeNames {
+		if err := fn(name); err != nil {
+			errs[name] = clustererr.Wrap(clustererr.Internal, name, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
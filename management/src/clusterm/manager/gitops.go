@@ -0,0 +1,88 @@
+package manager
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/contiv/cluster/management/src/clustererr"
+	"github.com/contiv/cluster/management/src/configuration"
+	"github.com/contiv/cluster/management/src/gitops"
+)
+
+// gitopsBusyRetryInterval is the interval to retry a gitops step at, while another
+// job is active
+const gitopsBusyRetryInterval = 30 * time.Second
+
+// gitopsLoop watches the gitops repository and applies the manifest of every new commit
+func (m *Manager) gitopsLoop() {
+	m.gitops.Watch(m.applyGitOpsManifest)
+}
+
+// applyGitOpsManifest computes the plan for the manifest and runs it's steps one
+// after the other. The remaining steps are abandoned on a failure, to be re-planned
+// on the next commit.
+func (m *Manager) applyGitOpsManifest(commit string, manifest *gitops.Manifest) {
+	extraVars := configuration.DefaultValidJSON
+	if len(manifest.ExtraVars) > 0 {
+		vars, err := json.Marshal(manifest.ExtraVars)
+		if err != nil {
+			logrus.Errorf("failed to encode the extra vars of gitops commit %q. Error: %v", commit, err)
+			return
+		}
+		extraVars = string(vars)
+	}
+
+	pe := newGitOpsPlanEvent(m, commit, manifest)
+	me := newWaitableEvent(pe)
+	m.reqQ <- me
+	if err := me.waitForCompletion(); err != nil {
+		logrus.Errorf("failed to plan gitops commit %q. Error: %v", commit, err)
+		return
+	}
+	if len(pe.plan.Skipped) > 0 {
+		logrus.Warnf("gitops commit %q lists nodes that are not discovered or are busy, skipping them: %v",
+			commit, pe.plan.Skipped)
+	}
+	if len(pe.plan.Steps) == 0 {
+		logrus.Infof("cluster is in sync with gitops commit %q", commit)
+		return
+	}
+
+	for _, step := range pe.plan.Steps {
+		if err := m.runGitOpsStep(commit, extraVars, step); err != nil {
+			logrus.Errorf("failed to %s nodes %v for gitops commit %q, abandoning the remaining steps. Error: %v",
+				step.Action, step.Nodes, commit, err)
+			return
+		}
+	}
+	logrus.Infof("applied gitops commit %q", commit)
+}
+
+// runGitOpsStep triggers the step's workflow and waits for it's job to be done. The
+// step is retried while another job is active.
+func (m *Manager) runGitOpsStep(commit, extraVars string, step gitops.Step) error {
+	for {
+		se := newGitOpsStepEvent(m, commit, extraVars, step)
+		me := newWaitableEvent(se)
+		m.reqQ <- me
+		err := me.waitForCompletion()
+		if err == nil {
+			break
+		}
+		if !se.busy {
+			return err
+		}
+		logrus.Infof("waiting for the active job to finish before applying gitops commit %q", commit)
+		time.Sleep(gitopsBusyRetryInterval)
+	}
+
+	j := <-m.gitopsJobDone
+	if status, errVal := j.Status(); status == Errored {
+		if errVal == nil {
+			errVal = clustererr.New(clustererr.Internal, "job %s failed", j.id)
+		}
+		return errVal
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package manager
+
+import (
+	"fmt"
+
+	"github.com/contiv/cluster/management/src/clustererr"
+	"github.com/contiv/cluster/management/src/gitops"
+	"github.com/contiv/cluster/management/src/inventory"
+)
+
+// gitopsPlanEvent computes the plan to apply the manifest of a gitops commit,
+// against the current state of the cluster
+type gitopsPlanEvent struct {
+	mgr      *Manager
+	commit   string
+	manifest *gitops.Manifest
+
+	plan *gitops.Plan
+}
+
+// newGitOpsPlanEvent creates and returns gitopsPlanEvent
+func newGitOpsPlanEvent(mgr *Manager, commit string, manifest *gitops.Manifest) *gitopsPlanEvent {
+	return &gitopsPlanEvent{
+		mgr:      mgr,
+		commit:   commit,
+		manifest: manifest,
+	}
+}
+
+func (e *gitopsPlanEvent) String() string {
+	return fmt.Sprintf("gitopsPlanEvent: commit: %q", e.commit)
+}
+
+func (e *gitopsPlanEvent) process() error {
+	// only the discovered nodes that are not in the middle of a workflow, are
+	// considered for the plan
+	current := map[string]gitops.NodeState{}
+	discovered := stateSel(inventory.Discovered)
+	for _, name := range e.mgr.nodes.selectNames(nodeSelector{state: discovered, status: statusSel(inventory.Allocated)}) {
+		if n, ok := e.mgr.nodes.get(name); ok && n.Cfg != nil {
+			current[name] = gitops.NodeState{Commissioned: true, HostGroup: n.Cfg.GetGroup()}
+		}
+	}
+	for _, status := range []inventory.AssetStatus{inventory.Unallocated, inventory.Decommissioned} {
+		for _, name := range e.mgr.nodes.selectNames(nodeSelector{state: discovered, status: statusSel(status)}) {
+			current[name] = gitops.NodeState{Commissioned: false}
+		}
+	}
+	plan := gitops.ComputePlan(e.manifest, current)
+	if err := gitops.CheckDecommission(plan, current, e.mgr.gitops.Config().MaxDecommissionFraction); err != nil {
+		return clustererr.Wrap(clustererr.Validation, "", err)
+	}
+	e.plan = plan
+	return nil
+}
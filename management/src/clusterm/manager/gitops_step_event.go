@@ -0,0 +1,57 @@
+package manager
+
+import (
+	"fmt"
+
+	"github.com/contiv/cluster/management/src/clustererr"
+	"github.com/contiv/cluster/management/src/gitops"
+)
+
+// gitopsStepEvent triggers the workflow of a step of a gitops plan
+type gitopsStepEvent struct {
+	mgr       *Manager
+	commit    string
+	extraVars string
+	step      gitops.Step
+
+	// busy is set when the step couldn't be triggered due to another active job
+	busy bool
+}
+
+// newGitOpsStepEvent creates and returns gitopsStepEvent
+func newGitOpsStepEvent(mgr *Manager, commit, extraVars string, step gitops.Step) *gitopsStepEvent {
+	return &gitopsStepEvent{
+		mgr:       mgr,
+		commit:    commit,
+		extraVars: extraVars,
+		step:      step,
+	}
+}
+
+func (e *gitopsStepEvent) String() string {
+	return fmt.Sprintf("gitopsStepEvent: commit: %q action: %s nodes: %v host-group: %q",
+		e.commit, e.step.Action, e.step.Nodes, e.step.HostGroup)
+}
+
+func (e *gitopsStepEvent) process() error {
+	if e.mgr.activeJob != nil {
+		e.busy = true
+		return errActiveJob(e.mgr.activeJob.String())
+	}
+
+	switch e.step.Action {
+	case gitops.Commission:
+		ce := newCommissionEvent(e.mgr, e.step.Nodes, e.extraVars, e.step.HostGroup)
+		ce.commit = e.commit
+		return ce.process()
+	case gitops.Update:
+		ue := newUpdateEvent(e.mgr, e.step.Nodes, e.extraVars, e.step.HostGroup)
+		ue.commit = e.commit
+		return ue.process()
+	case gitops.Decommission:
+		de := newDecommissionEvent(e.mgr, e.step.Nodes, e.extraVars)
+		de.commit = e.commit
+		return de.process()
+	}
+	return clustererr.New(clustererr.Validation, "unknown gitops action %q", e.step.Action)
+}
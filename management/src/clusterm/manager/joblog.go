@@ -0,0 +1,281 @@
+package manager
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/contiv/errored"
+)
+
+const (
+	defaultJobLogTailSize = 256 * 1024
+	jobLogFileSuffix      = ".log"
+)
+
+// ringBuffer keeps the last len(buf) bytes written to it
+type ringBuffer struct {
+	buf  []byte
+	pos  int
+	full bool
+}
+
+func newRingBuffer(size int) *ringBuffer {
+	if size <= 0 {
+		size = defaultJobLogTailSize
+	}
+	return &ringBuffer{buf: make([]byte, size)}
+}
+
+// Write writes to the buffer, overwriting the oldest bytes once it is full
+func (r *ringBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if n >= len(r.buf) {
+		copy(r.buf, p[n-len(r.buf):])
+		r.pos = 0
+		r.full = true
+		return n, nil
+	}
+	end := r.pos + n
+	c := copy(r.buf[r.pos:], p)
+	copy(r.buf, p[c:])
+	if end >= len(r.buf) {
+		r.full = true
+	}
+	r.pos = end % len(r.buf)
+	return n, nil
+}
+
+// Bytes returns a copy of the buffered bytes, oldest first
+func (r *ringBuffer) Bytes() []byte {
+	if !r.full {
+		return append([]byte{}, r.buf[:r.pos]...)
+	}
+	return append(append([]byte{}, r.buf[r.pos:]...), r.buf[:r.pos]...)
+}
+
+// jobLog captures the logs of a job. The logs are written to a size capped file,
+// when one is configured and the last few logs are always kept in memory for tailing.
+// Once the file reaches it's cap the later logs are only kept in memory, so a
+// long running job's logs are bounded both in memory and on disk.
+type jobLog struct {
+	sync.Mutex
+	tail     *ringBuffer
+	dir      string
+	id       string
+	maxFiles int
+	file     *os.File
+	path     string
+	size     int64
+	maxSize  int64
+	written  int64
+}
+
+// newMemJobLog creates and returns a jobLog that only keeps the tail in memory
+func newMemJobLog(tailSize int) *jobLog {
+	return &jobLog{tail: newRingBuffer(tailSize)}
+}
+
+// newJobLog creates and returns a jobLog for the job, that is backed by a file
+// in the configured directory once it's opened. Atleast the job's own file is
+// retained, if the configured count of files is less than one
+func newJobLog(config jobLogsConfig, id string) *jobLog {
+	l := newMemJobLog(config.TailSizeKB * 1024)
+	l.dir = config.Dir
+	l.id = id
+	l.maxFiles = config.MaxFiles
+	if l.maxFiles < 1 {
+		l.maxFiles = 1
+	}
+	l.maxSize = int64(config.MaxFileSizeMB) * 1024 * 1024
+	return l
+}
+
+// open creates the log file of the job in the configured directory, if any. The
+// oldest log files in the directory are removed to keep atmost the configured
+// count of files and the logs written so far are copied to the file. It is
+// called once the job runs, so that the jobs that are rejected before they run
+// don't leave a file behind or prune the logs of the earlier jobs.
+func (l *jobLog) open() error {
+	l.Lock()
+	defer l.Unlock()
+	if l.dir == "" || l.path != "" {
+		return nil
+	}
+	tail := l.tail.Bytes()
+	if l.written > int64(len(tail)) {
+		return errored.Errorf("the logs written before the job started don't fit in the tail")
+	}
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return errored.Errorf("failed to create job logs directory %q. Error: %v", l.dir, err)
+	}
+	pruneJobLogs(l.dir, l.maxFiles-1)
+	path := filepath.Join(l.dir, l.id+jobLogFileSuffix)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return errored.Errorf("failed to create job log file %q. Error: %v", path, err)
+	}
+	l.path = path
+	l.file = f
+	l.writeFile(tail)
+	return nil
+}
+
+type byModTime []os.FileInfo
+
+func (f byModTime) Len() int           { return len(f) }
+func (f byModTime) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
+func (f byModTime) Less(i, j int) bool { return f[i].ModTime().Before(f[j].ModTime()) }
+
+// pruneJobLogs removes the oldest job log files in the directory, leaving atmost keep files
+func pruneJobLogs(dir string, keep int) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		logrus.Warnf("failed to read job logs directory %q. Error: %v", dir, err)
+		return
+	}
+	files := []os.FileInfo{}
+	for _, info := range infos {
+		if !info.IsDir() && strings.HasSuffix(info.Name(), jobLogFileSuffix) {
+			files = append(files, info)
+		}
+	}
+	if len(files) <= keep {
+		return
+	}
+	sort.Sort(byModTime(files))
+	for _, info := range files[:len(files)-keep] {
+		if err := os.Remove(filepath.Join(dir, info.Name())); err != nil {
+			logrus.Warnf("failed to remove job log file %q. Error: %v", info.Name(), err)
+		}
+	}
+}
+
+// Write writes the logs to the tail and to the file, until it reaches the cap
+func (l *jobLog) Write(p []byte) (int, error) {
+	l.Lock()
+	defer l.Unlock()
+	l.tail.Write(p)
+	l.written += int64(len(p))
+	l.writeFile(p)
+	return len(p), nil
+}
+
+// writeFile writes the logs to the file, if it's open and upto it's cap. It
+// expects the lock to be held
+func (l *jobLog) writeFile(p []byte) {
+	if l.file == nil {
+		return
+	}
+	toWrite := p
+	if l.maxSize > 0 && l.size+int64(len(p)) > l.maxSize {
+		toWrite = p[:l.maxSize-l.size]
+	}
+	n, err := l.file.Write(toWrite)
+	l.size += int64(n)
+	if err != nil {
+		// the logs continue to be kept in the tail
+		logrus.Errorf("failed to write to job log file %q, the later logs shall only be tailed. Error: %v",
+			l.path, err)
+		l.file.Close()
+		l.file = nil
+	}
+}
+
+// Close closes the log file, if any. The logs remain readable after close
+func (l *jobLog) Close() error {
+	l.Lock()
+	defer l.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// omitted returns the marker for the count of logs bytes that were dropped
+func omitted(count int64, path string) string {
+	if path == "" {
+		return fmt.Sprintf("... %d bytes of logs omitted ...\n", count)
+	}
+	return fmt.Sprintf("... %d bytes of logs omitted, log file %q reached it's size cap ...\n", count, path)
+}
+
+// Reader returns a reader over the logs captured so far. The logs are read from
+// the file, if any, with the logs written after the file reached it's cap read
+// from the tail.
+func (l *jobLog) Reader() io.Reader {
+	l.Lock()
+	defer l.Unlock()
+	tail := l.tail.Bytes()
+	if l.path == "" {
+		return bytes.NewReader(l.withMarker(tail))
+	}
+	f, err := os.Open(l.path)
+	if err != nil {
+		logrus.Warnf("failed to open job log file %q, only the tail is read. Error: %v", l.path, err)
+		return bytes.NewReader(l.withMarker(tail))
+	}
+	// the file is closed once it's read. The logs are only read upto the size
+	// at the time of call, as the job may still be writing them.
+	file := &closingReader{Reader: io.LimitReader(f, l.size), c: f}
+	if l.written == l.size {
+		return file
+	}
+	// leave out the part of tail that is also in the file
+	tailStart := l.written - int64(len(tail))
+	if tailStart < l.size {
+		tail = tail[l.size-tailStart:]
+		tailStart = l.size
+	}
+	rdrs := []io.Reader{file}
+	if tailStart > l.size {
+		rdrs = append(rdrs, strings.NewReader(omitted(tailStart-l.size, l.path)))
+	}
+	return io.MultiReader(append(rdrs, bytes.NewReader(tail))...)
+}
+
+// Path returns the path of the job's log file, it is empty until the file is opened
+func (l *jobLog) Path() string {
+	l.Lock()
+	defer l.Unlock()
+	return l.path
+}
+
+// Tail returns the last logs of the job, that are kept in memory
+func (l *jobLog) Tail() []byte {
+	l.Lock()
+	defer l.Unlock()
+	return l.withMarker(l.tail.Bytes())
+}
+
+// withMarker prefixes the tail with the omitted logs marker, if some logs
+// didn't fit in the tail. It expects the lock to be held
+func (l *jobLog) withMarker(tail []byte) []byte {
+	if dropped := l.written - int64(len(tail)); dropped > 0 {
+		return append([]byte(omitted(dropped, "")), tail...)
+	}
+	return tail
+}
+
+// closingReader closes the underlying reader on reaching EOF
+type closingReader struct {
+	io.Reader
+	c io.Closer
+}
+
+func (r *closingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err != nil {
+		r.c.Close()
+	}
+	return n, err
+}
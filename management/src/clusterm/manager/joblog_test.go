@@ -0,0 +1,128 @@
+// +build unittest
+
+package manager
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type jobLogSuite struct {
+}
+
+var _ = Suite(&jobLogSuite{})
+
+func (s *jobLogSuite) TestRingBuffer(c *C) {
+	r := newRingBuffer(8)
+	r.Write([]byte("abc"))
+	c.Assert(string(r.Bytes()), Equals, "abc")
+	r.Write([]byte("defgh"))
+	c.Assert(string(r.Bytes()), Equals, "abcdefgh")
+	r.Write([]byte("ij"))
+	c.Assert(string(r.Bytes()), Equals, "cdefghij")
+	r.Write([]byte("0123456789"))
+	c.Assert(string(r.Bytes()), Equals, "23456789")
+}
+
+func (s *jobLogSuite) TestMemJobLog(c *C) {
+	l := newMemJobLog(8)
+	l.Write([]byte("foo\n"))
+	out, err := ioutil.ReadAll(l.Reader())
+	c.Assert(err, IsNil)
+	c.Assert(string(out), Equals, "foo\n")
+
+	// only the tail is kept once the logs exceed it's size
+	l.Write([]byte("bar\nbaz\n"))
+	out, err = ioutil.ReadAll(l.Reader())
+	c.Assert(err, IsNil)
+	c.Assert(string(out), Equals, omitted(4, "")+"bar\nbaz\n")
+	c.Assert(l.Tail(), DeepEquals, out)
+}
+
+func (s *jobLogSuite) TestFileJobLog(c *C) {
+	dir, err := ioutil.TempDir("", "joblogs")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	l := newJobLog(jobLogsConfig{Dir: dir, MaxFileSizeMB: 1, TailSizeKB: 1}, "job1")
+	line := strings.Repeat("x", 1023) + "\n"
+	l.Write([]byte(line))
+	// the file is only created once the log is opened, with the logs written so far
+	_, err = os.Stat(filepath.Join(dir, "job1.log"))
+	c.Assert(os.IsNotExist(err), Equals, true)
+	c.Assert(l.open(), IsNil)
+	c.Assert(l.path, Equals, filepath.Join(dir, "job1.log"))
+
+	// the logs are read from the file, while the tail is bounded
+	for i := 0; i < 3; i++ {
+		l.Write([]byte(line))
+	}
+	out, err := ioutil.ReadAll(l.Reader())
+	c.Assert(err, IsNil)
+	c.Assert(string(out), Equals, strings.Repeat(line, 4))
+	c.Assert(string(l.Tail()), Equals, omitted(3*1024, "")+line)
+
+	// the logs after the file reaches it's cap are read from the tail
+	for i := 0; i < 1024; i++ {
+		l.Write([]byte(line))
+	}
+	l.Write([]byte("last\n"))
+	c.Assert(l.Close(), IsNil)
+	fi, err := os.Stat(l.path)
+	c.Assert(err, IsNil)
+	c.Assert(fi.Size(), Equals, int64(1024*1024))
+	out, err = ioutil.ReadAll(l.Reader())
+	c.Assert(err, IsNil)
+	// the file has the first 1024 lines, the tail the last 1024 bytes
+	exptdOut := strings.Repeat(line, 1024) + omitted(3*1024+5, l.path) + (line + "last\n")[5:]
+	c.Assert(string(out) == exptdOut, Equals, true)
+}
+
+func (s *jobLogSuite) TestPruneJobLogs(c *C) {
+	dir, err := ioutil.TempDir("", "joblogs")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	for i := 0; i < 4; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("job%d.log", i))
+		c.Assert(ioutil.WriteFile(path, []byte("foo"), 0644), IsNil)
+		mtime := now.Add(time.Duration(i-4) * time.Minute)
+		c.Assert(os.Chtimes(path, mtime, mtime), IsNil)
+	}
+	c.Assert(ioutil.WriteFile(filepath.Join(dir, "other"), []byte("foo"), 0644), IsNil)
+
+	// opening a job's log leaves atmost the configured count of files
+	l := newJobLog(jobLogsConfig{Dir: dir, MaxFiles: 3}, "job4")
+	infos, err := ioutil.ReadDir(dir)
+	c.Assert(err, IsNil)
+	c.Assert(infos, HasLen, 5)
+	c.Assert(l.open(), IsNil)
+	c.Assert(l.Close(), IsNil)
+	infos, err = ioutil.ReadDir(dir)
+	c.Assert(err, IsNil)
+	names := []string{}
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	c.Assert(names, DeepEquals, []string{"job2.log", "job3.log", "job4.log", "other"})
+
+	// only the job's own file is left when the count of files is not positive
+	l = newJobLog(jobLogsConfig{Dir: dir, MaxFiles: -1}, "job5")
+	c.Assert(l.open(), IsNil)
+	c.Assert(l.Close(), IsNil)
+	c.Assert(l.Path(), Equals, filepath.Join(dir, "job5.log"))
+	infos, err = ioutil.ReadDir(dir)
+	c.Assert(err, IsNil)
+	names = []string{}
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	c.Assert(names, DeepEquals, []string{"job5.log", "other"})
+}
@@ -1,19 +1,23 @@
 package manager
 
 import (
-	"bytes"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"reflect"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
-	"github.com/contiv/errored"
+	"github.com/Sirupsen/logrus"
+	"github.com/contiv/cluster/management/src/clustererr"
 )
 
-var notRunningErr = errored.Errorf("job is not Running")
+var notRunningErr = clustererr.New(clustererr.Conflict, "job is not Running")
 
 // CancelChannel is type of the channle used to signal cancellation of job
 type CancelChannel chan struct{}
@@ -25,34 +29,62 @@ type JobRunner func(cancelCh CancelChannel, logs io.Writer) error
 // DoneCallback is called when job completes, errors or is cancelled
 type DoneCallback func(status JobStatus, errVal error)
 
+// jobContext is the info about the event that triggered a job
+type jobContext struct {
+	eventType string
+	nodes     []string
+	// commit is the gitops commit that the job applies, if any
+	commit string
+}
+
 // Job corresponds to a long running task, triggered by an event
 type Job struct {
 	sync.Mutex
+	id        string
+	ctxt      jobContext
 	runner    JobRunner
 	done      DoneCallback
 	cancelCh  CancelChannel
 	status    JobStatus
 	errVal    error
-	logs      bytes.Buffer
+	logs      *jobLog
 	logWriter *MultiWriter
 	desc      string
 }
 
 // NewJob initializes and returns an instance of a job described by the runner and done callback
 func NewJob(desc string, jr JobRunner, done DoneCallback) *Job {
+	return newJob(newJobID(), desc, jr, done, newMemJobLog(defaultJobLogTailSize))
+}
+
+// newJob initializes and returns an instance of a job with the id, that captures
+// it's logs in the specified jobLog
+func newJob(id, desc string, jr JobRunner, done DoneCallback, logs *jobLog) *Job {
 	j := &Job{
+		id:        id,
 		runner:    jr,
 		done:      done,
 		desc:      desc,
 		cancelCh:  make(chan struct{}),
 		status:    Queued,
 		errVal:    nil,
+		logs:      logs,
 		logWriter: &MultiWriter{},
 	}
-	j.logWriter.Add(&j.logs)
+	j.logWriter.Add(j.logs)
 	return j
 }
 
+// newJobID returns a random id for a job
+func newJobID() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		// fallback to a time based id
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(id)
+}
+
 func (j *Job) runnerName() string {
 	return runtime.FuncForPC(reflect.ValueOf(j.runner).Pointer()).Name()
 }
@@ -71,6 +103,9 @@ func (j *Job) setStatus(status JobStatus, err error) {
 
 // Run begins the job and wait for completion. This function blocks
 func (j *Job) Run() {
+	if err := j.logs.open(); err != nil {
+		logrus.Warnf("job logs shall only be tailed in memory. Error: %v", err)
+	}
 	j.setStatus(Running, nil)
 	defer func() {
 		j.done(j.status, j.errVal)
@@ -104,10 +139,9 @@ func (j *Job) Status() (JobStatus, error) {
 
 // Logs returns the current logs associated with the job.
 func (j *Job) Logs() io.Reader {
-	// instead of returning the buffer itself we instead need to return
-	// a reader created over current contents of the buffer without changing
-	// it's read offset. This will allow accessing logs over and over again.
-	return bytes.NewReader(j.logs.Bytes())
+	// a new reader is returned over the current logs on every call. This will
+	// allow accessing logs over and over again.
+	return j.logs.Reader()
 }
 
 // PipeLogs pipes the job logs to the specified writer (in addition to underlying log buffer).
@@ -123,19 +157,34 @@ func (j *Job) PipeLogs(w io.Writer) error {
 // MarshalJSON marshals and returns the JSON for job info
 func (j *Job) MarshalJSON() ([]byte, error) {
 	toJSON := struct {
-		Desc   string   `json:"desc"`
-		Task   string   `json:"task"`
-		Status string   `json:"status"`
-		ErrVal string   `json:"error"`
-		Logs   []string `json:"logs"`
+		ID        string   `json:"id"`
+		Desc      string   `json:"desc"`
+		EventType string   `json:"event_type,omitempty"`
+		Nodes     []string `json:"nodes,omitempty"`
+		Commit    string   `json:"commit,omitempty"`
+		Task      string   `json:"task"`
+		Status    string   `json:"status"`
+		ErrVal    string   `json:"error"`
+		// ErrInfo is the code and node(s) of the error, in addition to it's description
+		ErrInfo *clustererr.Info `json:"error_info,omitempty"`
+		Logs    []string         `json:"logs"`
+		LogFile string           `json:"log_file,omitempty"`
 	}{
-		Desc:   j.desc,
-		Task:   j.runnerName(),
-		Status: j.status.String(),
-		Logs:   strings.Split(j.logs.String(), "\n"),
+		ID:        j.id,
+		Desc:      j.desc,
+		EventType: j.ctxt.eventType,
+		Nodes:     j.ctxt.nodes,
+		Commit:    j.ctxt.commit,
+		Task:      j.runnerName(),
+		Status:    j.status.String(),
+		// only the tail of the logs is returned, the complete logs are
+		// available in the log file
+		Logs:    strings.Split(string(j.logs.Tail()), "\n"),
+		LogFile: j.logs.Path(),
 	}
 	if j.errVal != nil {
 		toJSON.ErrVal = fmt.Sprintf("%v", j.errVal)
+		toJSON.ErrInfo = clustererr.InfoOf(j.errVal)
 	}
 
 	return json.Marshal(toJSON)
@@ -249,8 +249,9 @@ func (s *jobsSuite) TestJobInfoMarshal(c *C) {
 		desc:   exptdDesc,
 		status: Running,
 		errVal: exptdErr,
-		logs:   *bytes.NewBuffer([]byte(exptdLogStr)),
+		logs:   newMemJobLog(defaultJobLogTailSize),
 	}
+	j.logs.Write([]byte(exptdLogStr))
 
 	out, err := j.MarshalJSON()
 	c.Assert(err, IsNil)
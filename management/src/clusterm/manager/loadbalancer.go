@@ -0,0 +1,107 @@
+package manager
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/contiv/cluster/management/src/lb"
+)
+
+// lbMembers returns the load balancer pool members for the specified nodes. Only
+// the nodes in the configured host-groups are members. It returns nil if load
+// balancer pool membership is not configured.
+func (m *Manager) lbMembers(enodes map[string]*node) []*lb.Member {
+	if m.lb == nil {
+		return nil
+	}
+	groups := map[string]struct{}{}
+	for _, group := range m.config.LoadBalancer.HostGroups {
+		groups[group] = struct{}{}
+	}
+	members := []*lb.Member{}
+	for name, node := range enodes {
+		if node.Mon == nil || node.Cfg == nil {
+			logrus.Infof("node %q shall not be a load balancer pool member as it's monitoring or configuration info is missing", name)
+			continue
+		}
+		if _, ok := groups[node.Cfg.GetGroup()]; len(groups) > 0 && !ok {
+			continue
+		}
+		members = append(members, &lb.Member{
+			Name: name,
+			Addr: node.Mon.GetMgmtAddress(),
+		})
+	}
+	return members
+}
+
+// staleLBMembers returns the members in oldMembers that are not in newMembers
+func staleLBMembers(oldMembers, newMembers []*lb.Member) []*lb.Member {
+	names := map[string]struct{}{}
+	for _, m := range newMembers {
+		names[m.Name] = struct{}{}
+	}
+	stale := []*lb.Member{}
+	for _, m := range oldMembers {
+		if _, ok := names[m.Name]; !ok {
+			stale = append(stale, m)
+		}
+	}
+	return stale
+}
+
+type lbCallback func(m *lb.Member) error
+
+// updateLBMembers runs the load balancer action on the specified members. It
+// continues on failures, which are logged, and returns the count of failures.
+func updateLBMembers(members []*lb.Member, action string, lbCb lbCallback, jobLogs io.Writer) int {
+	failed := 0
+	for _, m := range members {
+		fmt.Fprintf(jobLogs, "%s load balancer pool member %s\n", action, m)
+		if err := lbCb(m); err != nil {
+			logrus.Errorf("failed to %s load balancer pool member %s. Error: %v", action, m, err)
+			fmt.Fprintf(jobLogs, "failed to %s load balancer pool member %s. Error: %v\n", action, m, err)
+			failed++
+		}
+	}
+	return failed
+}
+
+// registerLBMembers adds the specified members to the load balancer pool, if configured
+func (m *Manager) registerLBMembers(members []*lb.Member, jobLogs io.Writer) {
+	if m.lb == nil {
+		return
+	}
+	updateLBMembers(members, "register", m.lb.Register, jobLogs)
+}
+
+// deregisterLBMembers removes the specified members from the load balancer pool, if configured
+func (m *Manager) deregisterLBMembers(members []*lb.Member, jobLogs io.Writer) {
+	if m.lb == nil {
+		return
+	}
+	updateLBMembers(members, "deregister", m.lb.Deregister, jobLogs)
+}
+
+// drainLBMembers drains the specified members in the load balancer pool, if configured,
+// and waits for the configured time for the connections to drain. It returns early
+// with an error if the job is cancelled.
+func (m *Manager) drainLBMembers(members []*lb.Member, cancelCh CancelChannel, jobLogs io.Writer) error {
+	if m.lb == nil || len(members) == 0 {
+		return nil
+	}
+	if failed := updateLBMembers(members, "drain", m.lb.Drain, jobLogs); failed == len(members) {
+		// there is nothing to wait for if no member could be drained
+		return nil
+	}
+	drainWait := time.Duration(m.config.LoadBalancer.DrainWaitSecs) * time.Second
+	fmt.Fprintf(jobLogs, "waiting %s for the connections to drain\n", drainWait)
+	select {
+	case <-cancelCh:
+		return errJobCancelled
+	case <-time.After(drainWait):
+		return nil
+	}
+}
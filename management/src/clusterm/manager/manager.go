@@ -12,12 +12,28 @@
 package manager
 
 import (
+	"time"
+
+	"github.com/Sirupsen/logrus"
 	"github.com/contiv/cluster/management/src/boltdb"
+	"github.com/contiv/cluster/management/src/bootstrap"
+	"github.com/contiv/cluster/management/src/clustererr"
+	"github.com/contiv/cluster/management/src/cmdb"
 	"github.com/contiv/cluster/management/src/configuration"
+	"github.com/contiv/cluster/management/src/dns"
+	"github.com/contiv/cluster/management/src/gitops"
 	"github.com/contiv/cluster/management/src/inventory"
 	boltdbinv "github.com/contiv/cluster/management/src/inventory/boltdb"
 	collinsinv "github.com/contiv/cluster/management/src/inventory/collins"
+	fakeinv "github.com/contiv/cluster/management/src/inventory/fake"
+	"github.com/contiv/cluster/management/src/lb"
+	"github.com/contiv/cluster/management/src/metrics"
 	"github.com/contiv/cluster/management/src/monitor"
+	"github.com/contiv/cluster/management/src/notify"
+	"github.com/contiv/cluster/management/src/power"
+	"github.com/contiv/cluster/management/src/publisher"
+	"github.com/contiv/cluster/management/src/remotelog"
+	"github.com/contiv/cluster/management/src/vault"
 	"github.com/contiv/errored"
 )
 
@@ -35,13 +51,26 @@ type Manager struct {
 	inventory     inventory.Subsys
 	configuration configuration.Subsys
 	monitor       monitor.Subsys
+	power         power.Subsys     // nil when power control of nodes is not configured
+	bootstrap     bootstrap.Subsys // nil when bare-metal bootstrap is not configured
+	dns           dns.Subsys       // nil when DNS registration of nodes is not configured
+	lb            lb.Subsys        // nil when load balancer pool membership is not configured
+	notifier      notify.Subsys    // nil when no notification channels are configured
+	publisher     publisher.Subsys // nil when event publishing is not configured
+	cmdb          cmdb.Subsys      // nil when CMDB sync is not configured
+	logShipper    remotelog.Subsys // nil when remote logging is not configured
+	metrics       metrics.Subsys   // nil when metrics emission is not configured
+	gitops        *gitops.Watcher  // nil when gitops mode is not configured
+	dnsNamer      *dns.RecordNamer
+	monitorEvents *monitorCoalescer
 	reqQ          chan event
 	addr          string
-	nodes         map[string]*node
+	nodes         *nodeStore
 	activeJob     *Job // there can be only one active job at a time
 	lastJob       *Job
 	config        *Config
-	configFile    string // file containing clusterm config, when clusterm is started with a config file
+	configFile    string    // file containing clusterm config, when clusterm is started with a config file
+	gitopsJobDone chan *Job // receives the jobs applying gitops commits, once they are done
 }
 
 // NewManager initializes and returns an instance of the Manager. It returns nil
@@ -59,16 +88,36 @@ func NewManager(config *Config, configFile string) (*Manager, error) {
 	}
 
 	m := &Manager{
-		monitor:       monitor.NewSerfSubsys(&config.Serf),
-		configuration: configuration.NewAnsibleSubsys(&config.Ansible),
-		reqQ:          make(chan event, 100),
-		addr:          config.Manager.Addr,
-		nodes:         make(map[string]*node),
-		config:        config,
-		configFile:    configFile,
-	}
-	// We give priority to boltdb inventory if both are set in config
-	if config.Inventory.BoltDB != nil {
+		reqQ:       make(chan event, 100),
+		addr:       config.Manager.Addr,
+		nodes:      newNodeStore(),
+		config:     config,
+		configFile: configFile,
+	}
+
+	// the fake monitoring and configuration subsystems are used to simulate a cluster
+	if config.Monitor.Fake != nil {
+		m.monitor = monitor.NewFakeSubsys(*config.Monitor.Fake)
+	} else {
+		m.monitor = monitor.NewSerfSubsys(&config.Serf, config.Monitor.Retry)
+	}
+	if config.Configuration.Fake != nil {
+		m.configuration = configuration.NewFakeSubsys(*config.Configuration.Fake)
+	} else {
+		ansibleSubsys := configuration.NewAnsibleSubsys(&config.Ansible)
+		if config.Vault != nil {
+			// secret references in extra vars and host vars are resolved from vault
+			ansibleSubsys.SetSecretResolver(vault.NewResolver(*config.Vault))
+		}
+		m.configuration = ansibleSubsys
+	}
+
+	// We give priority to fake inventory, followed by boltdb, if more than one are set in config
+	if config.Inventory.Fake != nil {
+		if m.inventory, err = fakeinv.NewFakeSubsys(*config.Inventory.Fake); err != nil {
+			return nil, err
+		}
+	} else if config.Inventory.BoltDB != nil {
 		if m.inventory, err = boltdbinv.NewBoltdbSubsys(*config.Inventory.BoltDB); err != nil {
 			return nil, err
 		}
@@ -83,17 +132,119 @@ func NewManager(config *Config, configFile string) (*Manager, error) {
 		}
 	}
 
+	// We give priority to redfish if both are set in config
+	if config.Power.Redfish != nil {
+		m.power = power.NewRedfishSubsys(*config.Power.Redfish)
+	} else if config.Power.IPMI != nil {
+		m.power = power.NewIPMISubsys(*config.Power.IPMI)
+	}
+
+	if config.Bootstrap != nil {
+		if m.bootstrap, err = bootstrap.NewPXESubsys(*config.Bootstrap); err != nil {
+			return nil, err
+		}
+	}
+
+	// We give priority to route53, followed by bind, if more than one is set in config
+	if config.DNS.Route53 != nil {
+		m.dns = dns.NewRoute53Subsys(*config.DNS.Route53)
+	} else if config.DNS.Bind != nil {
+		m.dns = dns.NewBindSubsys(*config.DNS.Bind)
+	} else if config.DNS.CoreDNS != nil {
+		m.dns = dns.NewCoreDNSSubsys(*config.DNS.CoreDNS)
+	}
+	if m.dns != nil {
+		if m.dnsNamer, err = dns.NewRecordNamer(config.DNS.RecordName, config.DNS.HostGroupRecordNames); err != nil {
+			return nil, err
+		}
+	}
+
+	// We give priority to haproxy if both are set in config
+	if config.LoadBalancer.HAProxy != nil {
+		m.lb = lb.NewHAProxySubsys(*config.LoadBalancer.HAProxy)
+	} else if config.LoadBalancer.AWSTargetGroup != nil {
+		m.lb = lb.NewTargetGroupSubsys(*config.LoadBalancer.AWSTargetGroup)
+	}
+
+	if len(config.Notifications.Channels) > 0 {
+		if m.notifier, err = notify.NewDispatcher(config.Notifications.Channels,
+			config.Notifications.Templates); err != nil {
+			return nil, err
+		}
+	}
+
+	// We give priority to nats if both are set in config
+	if config.Events.NATS != nil {
+		m.publisher = publisher.NewAsyncPublisher(publisher.NewNATSDriver(*config.Events.NATS))
+	} else if config.Events.Kafka != nil {
+		m.publisher = publisher.NewAsyncPublisher(publisher.NewKafkaDriver(*config.Events.Kafka))
+	}
+
+	// We give priority to servicenow if both are set in config
+	var (
+		cmdbDriver   cmdb.Driver
+		cmdbFieldMap = config.CMDB.FieldMap
+	)
+	if config.CMDB.ServiceNow != nil {
+		cmdbDriver = cmdb.NewServiceNowDriver(*config.CMDB.ServiceNow)
+		if len(cmdbFieldMap) == 0 {
+			cmdbFieldMap = cmdb.DefaultServiceNowFieldMap
+		}
+	} else if config.CMDB.REST != nil {
+		cmdbDriver = cmdb.NewRESTDriver(*config.CMDB.REST)
+		if len(cmdbFieldMap) == 0 {
+			cmdbFieldMap = cmdb.DefaultRESTFieldMap
+		}
+	}
+	if cmdbDriver != nil {
+		if m.cmdb, err = cmdb.NewAsyncSyncer(cmdbDriver, cmdbFieldMap, config.CMDB.Retry); err != nil {
+			return nil, err
+		}
+	}
+
+	// We give priority to syslog if both are set in config
+	if config.RemoteLogging.Syslog != nil {
+		m.logShipper = remotelog.NewAsyncShipper(remotelog.NewSyslogDriver(*config.RemoteLogging.Syslog))
+	} else if config.RemoteLogging.Fluentd != nil {
+		m.logShipper = remotelog.NewAsyncShipper(remotelog.NewFluentdDriver(*config.RemoteLogging.Fluentd))
+	}
+	if m.logShipper != nil {
+		// ship the daemon logs. The job logs are shipped as the jobs are created
+		logrus.AddHook(remotelog.NewHook(m.logShipper))
+	}
+
+	// We give priority to statsd if both are set in config
+	if config.Metrics.StatsD != nil {
+		m.metrics = metrics.NewAsyncEmitter(metrics.NewStatsDDriver(*config.Metrics.StatsD))
+	} else if config.Metrics.Graphite != nil {
+		m.metrics = metrics.NewAsyncEmitter(metrics.NewGraphiteDriver(*config.Metrics.Graphite))
+	}
+
+	if config.GitOps != nil {
+		m.gitops = gitops.NewWatcher(*config.GitOps)
+		m.gitopsJobDone = make(chan *Job, 1)
+	}
+
+	m.monitorEvents = newMonitorCoalescer(time.Duration(config.Manager.MonitorCoalesceMsecs)*time.Millisecond,
+		config.Manager.MonitorBatchSize, NewClient(m.addr).PostMonitorEvent)
 	if err := m.monitor.RegisterCb(monitor.Discovered, m.enqueueMonitorEvent); err != nil {
-		return nil, errored.Errorf("failed to register node discovery callback. Error: %s", err)
+		return nil, clustererr.New(clustererr.Monitor, "failed to register node discovery callback. Error: %s", err)
 	}
 
 	if err := m.monitor.RegisterCb(monitor.Disappeared, m.enqueueMonitorEvent); err != nil {
-		return nil, errored.Errorf("failed to register node disappearance callback. Error: %s", err)
+		return nil, clustererr.New(clustererr.Monitor, "failed to register node disappearance callback. Error: %s", err)
 	}
 
 	return m, nil
 }
 
+// FakeMonitor returns the fake monitoring subsystem, that the simulated nodes join
+// and fail through. It returns nil when clusterm is not running in simulation mode.
+func (m *Manager) FakeMonitor() *monitor.FakeSubsys {
+	fm, _ := m.monitor.(*monitor.FakeSubsys)
+	return fm
+}
+
 // Run triggers the manager loops
 func (m *Manager) Run(errCh chan error) {
 
@@ -114,4 +265,16 @@ func (m *Manager) Run(errCh chan error) {
 
 	// start the event loop. It processes the events.
 	go m.eventLoop()
+
+	// start the metrics loop, if metrics emission is configured. It feeds the
+	// periodic metrics events.
+	if m.metrics != nil {
+		go m.metricsLoop()
+	}
+
+	// start the gitops loop, if gitops mode is configured. It feeds the events
+	// applying the manifests of new commits.
+	if m.gitops != nil {
+		go m.gitopsLoop()
+	}
 }
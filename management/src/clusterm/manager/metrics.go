@@ -0,0 +1,63 @@
+package manager
+
+import (
+	"time"
+
+	"github.com/contiv/cluster/management/src/inventory"
+	"github.com/contiv/cluster/management/src/metrics"
+)
+
+// metricsLoop periodically enqueues the metrics event, so that the gauges are
+// collected in the event loop
+func (m *Manager) metricsLoop() {
+	interval := time.Duration(m.config.Metrics.IntervalSecs) * time.Second
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	for range time.Tick(interval) {
+		m.reqQ <- newMetricsEvent(m)
+	}
+}
+
+// emitJobMetrics emits the duration and the outcome of a job that is done
+func (m *Manager) emitJobMetrics(j *Job, d time.Duration) {
+	if m.metrics == nil {
+		return
+	}
+	eventType := j.ctxt.eventType
+	if eventType == "" {
+		eventType = "unknown"
+	}
+	m.metrics.Timing(metrics.Name("jobs", eventType, "duration"), d)
+	if status, _ := j.Status(); status == Errored {
+		m.metrics.Incr(metrics.Name("jobs", eventType, "failed"))
+	} else {
+		m.metrics.Incr(metrics.Name("jobs", eventType, "completed"))
+	}
+}
+
+// emitGauges emits the event queue depth, active job and the count of nodes in
+// each inventory status and state
+func (m *Manager) emitGauges() {
+	if m.metrics == nil {
+		return
+	}
+	m.metrics.Gauge(metrics.Name("events", "queue_depth"), int64(len(m.reqQ)))
+	activeJobs := int64(0)
+	if m.activeJob != nil {
+		activeJobs = 1
+	}
+	m.metrics.Gauge(metrics.Name("jobs", "active"), activeJobs)
+
+	// all the status and states are emitted, including the ones without any
+	// nodes, so that the gauges don't retain a stale count
+	m.metrics.Gauge(metrics.Name("nodes", "total"), int64(m.nodes.len()))
+	for status := inventory.Incomplete; status < inventory.Any; status++ {
+		m.metrics.Gauge(metrics.Name("nodes", "status", status.String()),
+			int64(m.nodes.count(nodeSelector{status: statusSel(status)})))
+	}
+	for state := inventory.Unknown; state <= inventory.Disappeared; state++ {
+		m.metrics.Gauge(metrics.Name("nodes", "state", state.String()),
+			int64(m.nodes.count(nodeSelector{state: stateSel(state)})))
+	}
+}
@@ -0,0 +1,22 @@
+package manager
+
+// metricsEvent emits the periodic metrics gauges
+type metricsEvent struct {
+	mgr *Manager
+}
+
+// newMetricsEvent creates and returns metricsEvent
+func newMetricsEvent(mgr *Manager) *metricsEvent {
+	return &metricsEvent{
+		mgr: mgr,
+	}
+}
+
+func (e *metricsEvent) String() string {
+	return "metricsEvent"
+}
+
+func (e *metricsEvent) process() error {
+	e.mgr.emitGauges()
+	return nil
+}
@@ -1,41 +1,108 @@
 package manager
 
 import (
+	"sync"
+	"time"
+
 	"github.com/Sirupsen/logrus"
+	"github.com/contiv/cluster/management/src/clustererr"
 	"github.com/contiv/cluster/management/src/monitor"
 )
 
-func (m *Manager) enqueueMonitorEvent(events []monitor.Event) {
-	// XXX: for now break the batch and inject one event per monitor event.
-	// revisit later as batching requirements become more clear
+// monitorCoalescer coalesces the bursts of monitor events, like the ones on a mass
+// reboot or a partition heal, and posts them as one event per type for a batch of
+// nodes. Only the latest event of a node is posted, when it changes more than once
+// in a burst.
+type monitorCoalescer struct {
+	sync.Mutex
+	// flushMutex serializes the flushes by the timer and on reaching the batch
+	// size, so that the batches are posted in the order the events were added
+	flushMutex sync.Mutex
+	window     time.Duration
+	batchSize  int
+	pending    map[string]monitor.Event
+	order      []string
+	timer      *time.Timer
+	post       func(eventName string, nodes []MonitorNode) error
+}
+
+// newMonitorCoalescer creates and returns a monitorCoalescer that posts the
+// pending events once the window elapses or the batch size is reached
+func newMonitorCoalescer(window time.Duration, batchSize int,
+	post func(eventName string, nodes []MonitorNode) error) *monitorCoalescer {
+	return &monitorCoalescer{
+		window:    window,
+		batchSize: batchSize,
+		pending:   make(map[string]monitor.Event),
+		post:      post,
+	}
+}
+
+// add adds the events to the pending batch
+func (c *monitorCoalescer) add(events []monitor.Event) {
+	c.Lock()
 	for _, e := range events {
 		logrus.Debugf("processing monitor event: %+v", e)
-		eventName := ""
-		switch e.Type {
-		case monitor.Discovered:
-			eventName = monitor.Discovered.String()
-		case monitor.Disappeared:
-			eventName = monitor.Disappeared.String()
-		default:
+		if e.Type != monitor.Discovered && e.Type != monitor.Disappeared {
 			logrus.Errorf("unexpected monitor event type %v", e.Type)
 			continue
 		}
-		if err := NewClient(m.addr).PostMonitorEvent(eventName,
-			[]MonitorNode{
-				{
-					Label:    e.Node.GetLabel(),
-					Serial:   e.Node.GetSerial(),
-					MgmtAddr: e.Node.GetMgmtAddress(),
-				},
-			}); err != nil {
-			logrus.Errorf("error posting monitor event %q. Error: %v", eventName, err)
+		name := e.Node.GetLabel() + "-" + e.Node.GetSerial()
+		if _, ok := c.pending[name]; !ok {
+			c.order = append(c.order, name)
+		}
+		c.pending[name] = e
+	}
+	flushNow := c.window <= 0 || (c.batchSize > 0 && len(c.order) >= c.batchSize)
+	if !flushNow && c.timer == nil && len(c.order) > 0 {
+		c.timer = time.AfterFunc(c.window, c.flush)
+	}
+	c.Unlock()
+
+	if flushNow {
+		c.flush()
+	}
+}
+
+// flush posts the pending events, one per event type
+func (c *monitorCoalescer) flush() {
+	c.flushMutex.Lock()
+	defer c.flushMutex.Unlock()
+	c.Lock()
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	batches := map[monitor.EventType][]MonitorNode{}
+	for _, name := range c.order {
+		e := c.pending[name]
+		batches[e.Type] = append(batches[e.Type], MonitorNode{
+			Label:    e.Node.GetLabel(),
+			Serial:   e.Node.GetSerial(),
+			MgmtAddr: e.Node.GetMgmtAddress(),
+		})
+	}
+	c.pending = make(map[string]monitor.Event)
+	c.order = nil
+	c.Unlock()
+
+	for _, t := range []monitor.EventType{monitor.Discovered, monitor.Disappeared} {
+		if len(batches[t]) == 0 {
+			continue
+		}
+		if err := c.post(t.String(), batches[t]); err != nil {
+			logrus.Errorf("error posting monitor event %q for %d node(s). Error: %v", t, len(batches[t]), err)
 		}
 	}
 }
 
+func (m *Manager) enqueueMonitorEvent(events []monitor.Event) {
+	m.monitorEvents.add(events)
+}
+
 func (m *Manager) monitorLoop(errCh chan error) {
 	if err := m.monitor.Start(); err != nil {
 		logrus.Errorf("monitoring subsystem encountered a failure. Error: %s", err)
-		errCh <- err
+		errCh <- clustererr.Wrap(clustererr.Monitor, "", err)
 	}
 }
@@ -0,0 +1,124 @@
+// +build unittest
+
+package manager
+
+import (
+	"sync"
+	"time"
+
+	"github.com/contiv/cluster/management/src/monitor"
+	. "gopkg.in/check.v1"
+)
+
+type monitorSuite struct {
+}
+
+var _ = Suite(&monitorSuite{})
+
+// postRecorder records the monitor events posted by the coalescer
+type postRecorder struct {
+	sync.Mutex
+	posted map[string][]MonitorNode
+	count  int
+}
+
+func (r *postRecorder) post(eventName string, nodes []MonitorNode) error {
+	r.Lock()
+	defer r.Unlock()
+	r.posted[eventName] = append(r.posted[eventName], nodes...)
+	r.count++
+	return nil
+}
+
+func monitorEvent(t monitor.EventType, label string) monitor.Event {
+	return monitor.Event{Type: t, Node: monitor.NewNode(label, "serial", label+"-addr")}
+}
+
+func (s *monitorSuite) TestCoalesceBurst(c *C) {
+	r := &postRecorder{posted: map[string][]MonitorNode{}}
+	mc := newMonitorCoalescer(100*time.Millisecond, 0, r.post)
+
+	// a burst is posted once per event type, with the latest event of each node
+	mc.add([]monitor.Event{monitorEvent(monitor.Disappeared, "node1"), monitorEvent(monitor.Disappeared, "node2")})
+	mc.add([]monitor.Event{monitorEvent(monitor.Disappeared, "node3")})
+	mc.add([]monitor.Event{monitorEvent(monitor.Discovered, "node1")})
+	r.Lock()
+	c.Assert(r.count, Equals, 0)
+	r.Unlock()
+
+	time.Sleep(300 * time.Millisecond)
+	r.Lock()
+	defer r.Unlock()
+	c.Assert(r.count, Equals, 2)
+	c.Assert(r.posted, DeepEquals, map[string][]MonitorNode{
+		monitor.Discovered.String(): {
+			{Label: "node1", Serial: "serial", MgmtAddr: "node1-addr"},
+		},
+		monitor.Disappeared.String(): {
+			{Label: "node2", Serial: "serial", MgmtAddr: "node2-addr"},
+			{Label: "node3", Serial: "serial", MgmtAddr: "node3-addr"},
+		},
+	})
+}
+
+func (s *monitorSuite) TestCoalesceBatchSize(c *C) {
+	r := &postRecorder{posted: map[string][]MonitorNode{}}
+	mc := newMonitorCoalescer(time.Hour, 2, r.post)
+
+	// the pending events are posted on reaching the batch size, without waiting for the window
+	mc.add([]monitor.Event{monitorEvent(monitor.Discovered, "node1")})
+	mc.add([]monitor.Event{monitorEvent(monitor.Discovered, "node2")})
+	mc.add([]monitor.Event{monitorEvent(monitor.Discovered, "node3")})
+	r.Lock()
+	c.Assert(r.count, Equals, 1)
+	c.Assert(r.posted[monitor.Discovered.String()], HasLen, 2)
+	r.Unlock()
+
+	// the events are posted right away when coalescing is disabled
+	mc = newMonitorCoalescer(0, 0, r.post)
+	mc.add([]monitor.Event{monitorEvent(monitor.Disappeared, "node1")})
+	r.Lock()
+	c.Assert(r.count, Equals, 2)
+	r.Unlock()
+}
+
+func (s *monitorSuite) TestCoalesceFlushOrder(c *C) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var mutex sync.Mutex
+	posted := []string{}
+	post := func(eventName string, nodes []MonitorNode) error {
+		mutex.Lock()
+		first := len(posted) == 0
+		for _, n := range nodes {
+			posted = append(posted, eventName+":"+n.Label)
+		}
+		mutex.Unlock()
+		if first {
+			// hold up the timer's flush while the batch size triggers another
+			close(started)
+			<-release
+		}
+		return nil
+	}
+	mc := newMonitorCoalescer(10*time.Millisecond, 2, post)
+
+	mc.add([]monitor.Event{monitorEvent(monitor.Disappeared, "node1")})
+	<-started
+	done := make(chan struct{})
+	go func() {
+		mc.add([]monitor.Event{monitorEvent(monitor.Discovered, "node1"), monitorEvent(monitor.Discovered, "node2")})
+		close(done)
+	}()
+
+	// the batch of newer events waits for the older batch to be posted
+	time.Sleep(100 * time.Millisecond)
+	mutex.Lock()
+	c.Assert(posted, DeepEquals, []string{"Disappeared:node1"})
+	mutex.Unlock()
+	close(release)
+	<-done
+	mutex.Lock()
+	defer mutex.Unlock()
+	c.Assert(posted, DeepEquals, []string{"Disappeared:node1", "Discovered:node1", "Discovered:node2"})
+}
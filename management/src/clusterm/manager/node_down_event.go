@@ -0,0 +1,66 @@
+package manager
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/contiv/cluster/management/src/monitor"
+	"github.com/contiv/cluster/management/src/notify"
+)
+
+// nodeDownEvent checks if a node that disappeared is still down after the threshold
+// and sends the node down notification
+type nodeDownEvent struct {
+	mgr       *Manager
+	nodeName  string
+	mon       monitor.SubsysNode
+	threshold time.Duration
+}
+
+// newNodeDownEvent creates and returns nodeDownEvent
+func newNodeDownEvent(mgr *Manager, nodeName string, mon monitor.SubsysNode, threshold time.Duration) *nodeDownEvent {
+	return &nodeDownEvent{
+		mgr:       mgr,
+		nodeName:  nodeName,
+		mon:       mon,
+		threshold: threshold,
+	}
+}
+
+func (e *nodeDownEvent) String() string {
+	return fmt.Sprintf("nodeDownEvent: node: %q threshold: %s", e.nodeName, e.threshold)
+}
+
+func (e *nodeDownEvent) process() error {
+	node, err := e.mgr.findNode(e.nodeName)
+	if err != nil {
+		return err
+	}
+
+	// a monitoring event received since the node disappeared updates the node's
+	// monitoring info, in which case the node came back up or is being watched
+	// for a later disappearance
+	if node.Mon != e.mon {
+		return nil
+	}
+	if isDiscovered, err := e.mgr.isDiscoveredNode(e.nodeName); err != nil || isDiscovered {
+		return err
+	}
+	if !e.mgr.isCommissionedNode(e.nodeName) {
+		return nil
+	}
+
+	hostGroup := ""
+	if node.Cfg != nil {
+		hostGroup = node.Cfg.GetGroup()
+	}
+	e.mgr.notify(&notify.Event{
+		Type:      notify.NodeDown,
+		Node:      e.nodeName,
+		HostGroup: hostGroup,
+		Details: map[string]string{
+			"down_for": e.threshold.String(),
+		},
+	})
+	return nil
+}
@@ -0,0 +1,258 @@
+package manager
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/contiv/cluster/management/src/inventory"
+)
+
+// nodeKeys are the values a node is indexed by
+type nodeKeys struct {
+	hostGroup string
+	hasInv    bool
+	status    inventory.AssetStatus
+	state     inventory.AssetState
+	label     string
+	addr      string
+}
+
+// nameSet is a set of node names
+type nameSet map[string]struct{}
+
+// nodeStore holds the nodes keyed by name. The nodes are also indexed by their
+// host-group, inventory status and state, monitoring label and management address.
+// As the node's info is updated in place, reindex needs to be called for a node
+// after it's host-group, inventory status or monitoring info changes.
+type nodeStore struct {
+	sync.RWMutex
+	nodes    map[string]*node
+	keys     map[string]nodeKeys
+	byGroup  map[string]nameSet
+	byStatus map[inventory.AssetStatus]nameSet
+	byState  map[inventory.AssetState]nameSet
+	byLabel  map[string]nameSet
+	byAddr   map[string]nameSet
+}
+
+// newNodeStore creates and returns an empty nodeStore
+func newNodeStore() *nodeStore {
+	return &nodeStore{
+		nodes:    make(map[string]*node),
+		keys:     make(map[string]nodeKeys),
+		byGroup:  make(map[string]nameSet),
+		byStatus: make(map[inventory.AssetStatus]nameSet),
+		byState:  make(map[inventory.AssetState]nameSet),
+		byLabel:  make(map[string]nameSet),
+		byAddr:   make(map[string]nameSet),
+	}
+}
+
+// keysOf returns the current index keys of the node
+func keysOf(n *node) nodeKeys {
+	k := nodeKeys{}
+	if n.Cfg != nil {
+		k.hostGroup = n.Cfg.GetGroup()
+	}
+	if n.Inv != nil {
+		k.hasInv = true
+		k.status, k.state = n.Inv.GetStatus()
+	}
+	if n.Mon != nil {
+		k.label = n.Mon.GetLabel()
+		k.addr = n.Mon.GetMgmtAddress()
+	}
+	return k
+}
+
+func addToSet(sets map[string]nameSet, key, name string) {
+	if _, ok := sets[key]; !ok {
+		sets[key] = nameSet{}
+	}
+	sets[key][name] = struct{}{}
+}
+
+func removeFromSet(sets map[string]nameSet, key, name string) {
+	delete(sets[key], name)
+	if len(sets[key]) == 0 {
+		delete(sets, key)
+	}
+}
+
+// unindex removes the node's entries from the indexes. It expects the lock to be held
+func (s *nodeStore) unindex(name string) {
+	k, ok := s.keys[name]
+	if !ok {
+		return
+	}
+	removeFromSet(s.byGroup, k.hostGroup, name)
+	removeFromSet(s.byLabel, k.label, name)
+	removeFromSet(s.byAddr, k.addr, name)
+	if k.hasInv {
+		delete(s.byStatus[k.status], name)
+		delete(s.byState[k.state], name)
+	}
+	delete(s.keys, name)
+}
+
+// index adds the node's entries to the indexes. It expects the lock to be held
+func (s *nodeStore) index(name string, n *node) {
+	k := keysOf(n)
+	addToSet(s.byGroup, k.hostGroup, name)
+	addToSet(s.byLabel, k.label, name)
+	addToSet(s.byAddr, k.addr, name)
+	if k.hasInv {
+		if _, ok := s.byStatus[k.status]; !ok {
+			s.byStatus[k.status] = nameSet{}
+		}
+		s.byStatus[k.status][name] = struct{}{}
+		if _, ok := s.byState[k.state]; !ok {
+			s.byState[k.state] = nameSet{}
+		}
+		s.byState[k.state][name] = struct{}{}
+	}
+	s.keys[name] = k
+}
+
+// add adds or replaces the node with the name
+func (s *nodeStore) add(name string, n *node) {
+	s.Lock()
+	defer s.Unlock()
+	s.unindex(name)
+	s.nodes[name] = n
+	s.index(name, n)
+}
+
+// reindex updates the indexes with the current info of the node, if it exists
+func (s *nodeStore) reindex(name string) {
+	s.Lock()
+	defer s.Unlock()
+	n, ok := s.nodes[name]
+	if !ok {
+		return
+	}
+	s.unindex(name)
+	s.index(name, n)
+}
+
+// get returns the node with the name, if it exists
+func (s *nodeStore) get(name string) (*node, bool) {
+	s.RLock()
+	defer s.RUnlock()
+	n, ok := s.nodes[name]
+	return n, ok
+}
+
+// getByAddr returns a node with the management address, if it exists
+func (s *nodeStore) getByAddr(addr string) (*node, bool) {
+	s.RLock()
+	defer s.RUnlock()
+	for name := range s.byAddr[addr] {
+		return s.nodes[name], true
+	}
+	return nil, false
+}
+
+// len returns the count of nodes
+func (s *nodeStore) len() int {
+	s.RLock()
+	defer s.RUnlock()
+	return len(s.nodes)
+}
+
+// names returns the names of all the nodes, in sorted order
+func (s *nodeStore) names() []string {
+	s.RLock()
+	defer s.RUnlock()
+	names := []string{}
+	for name := range s.nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// nodeSelector selects the nodes that match all the set fields
+type nodeSelector struct {
+	hostGroup string
+	status    *inventory.AssetStatus
+	state     *inventory.AssetState
+	label     string
+}
+
+func statusSel(status inventory.AssetStatus) *inventory.AssetStatus {
+	return &status
+}
+
+func stateSel(state inventory.AssetState) *inventory.AssetState {
+	return &state
+}
+
+// selectNames returns the names of the nodes that match the selector, in sorted order
+func (s *nodeStore) selectNames(sel nodeSelector) []string {
+	s.RLock()
+	defer s.RUnlock()
+	names := s.match(sel)
+	sort.Strings(names)
+	return names
+}
+
+// count returns the count of nodes that match the selector
+func (s *nodeStore) count(sel nodeSelector) int {
+	s.RLock()
+	defer s.RUnlock()
+	return len(s.match(sel))
+}
+
+// match returns the names of the nodes that match the selector. It expects the
+// lock to be held
+func (s *nodeStore) match(sel nodeSelector) []string {
+	// intersect the sets of the set fields, starting with the smallest
+	sets := []nameSet{}
+	if sel.hostGroup != "" {
+		sets = append(sets, s.byGroup[sel.hostGroup])
+	}
+	if sel.status != nil {
+		sets = append(sets, s.byStatus[*sel.status])
+	}
+	if sel.state != nil {
+		sets = append(sets, s.byState[*sel.state])
+	}
+	if sel.label != "" {
+		sets = append(sets, s.byLabel[sel.label])
+	}
+	names := []string{}
+	if len(sets) == 0 {
+		for name := range s.nodes {
+			names = append(names, name)
+		}
+		return names
+	}
+	smallest := 0
+	for i := range sets {
+		if len(sets[i]) < len(sets[smallest]) {
+			smallest = i
+		}
+	}
+	for name := range sets[smallest] {
+		matches := true
+		for _, set := range sets {
+			if _, ok := set[name]; !ok {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// MarshalJSON marshals the nodes keyed by their name
+func (s *nodeStore) MarshalJSON() ([]byte, error) {
+	s.RLock()
+	defer s.RUnlock()
+	return json.Marshal(s.nodes)
+}
@@ -0,0 +1,78 @@
+// +build unittest
+
+package manager
+
+import (
+	"github.com/contiv/cluster/management/src/configuration"
+	"github.com/contiv/cluster/management/src/inventory"
+	"github.com/contiv/cluster/management/src/monitor"
+	. "gopkg.in/check.v1"
+)
+
+type nodeStoreSuite struct {
+}
+
+var _ = Suite(&nodeStoreSuite{})
+
+func newTestNode(name, hostGroup string, status inventory.AssetStatus, state inventory.AssetState) *node {
+	return &node{
+		Mon: monitor.NewNode(name+"-label", name, name+"-addr"),
+		Inv: &fakeAsset{status: status, state: state},
+		Cfg: configuration.NewAnsibleHost(name, name+"-addr", hostGroup, nil),
+	}
+}
+
+func (s *nodeStoreSuite) TestSelect(c *C) {
+	ns := newNodeStore()
+	ns.add("node3", newTestNode("node3", ansibleWorkerGroupName, inventory.Allocated, inventory.Discovered))
+	ns.add("node1", newTestNode("node1", ansibleMasterGroupName, inventory.Allocated, inventory.Discovered))
+	ns.add("node2", newTestNode("node2", ansibleMasterGroupName, inventory.Allocated, inventory.Disappeared))
+	ns.add("node4", newTestNode("node4", "", inventory.Unallocated, inventory.Discovered))
+
+	c.Assert(ns.len(), Equals, 4)
+	c.Assert(ns.names(), DeepEquals, []string{"node1", "node2", "node3", "node4"})
+	c.Assert(ns.selectNames(nodeSelector{}), DeepEquals, []string{"node1", "node2", "node3", "node4"})
+	c.Assert(ns.selectNames(nodeSelector{hostGroup: ansibleMasterGroupName}), DeepEquals,
+		[]string{"node1", "node2"})
+	c.Assert(ns.selectNames(nodeSelector{
+		hostGroup: ansibleMasterGroupName,
+		state:     stateSel(inventory.Discovered),
+	}), DeepEquals, []string{"node1"})
+	c.Assert(ns.selectNames(nodeSelector{status: statusSel(inventory.Allocated)}), DeepEquals,
+		[]string{"node1", "node2", "node3"})
+	c.Assert(ns.selectNames(nodeSelector{label: "node4-label"}), DeepEquals, []string{"node4"})
+	c.Assert(ns.count(nodeSelector{status: statusSel(inventory.Decommissioned)}), Equals, 0)
+	c.Assert(ns.count(nodeSelector{hostGroup: "foo"}), Equals, 0)
+
+	n, ok := ns.getByAddr("node3-addr")
+	c.Assert(ok, Equals, true)
+	c.Assert(n.Cfg.GetTag(), Equals, "node3")
+	_, ok = ns.getByAddr("foo")
+	c.Assert(ok, Equals, false)
+}
+
+func (s *nodeStoreSuite) TestReindex(c *C) {
+	ns := newNodeStore()
+	ns.add("node1", newTestNode("node1", "", inventory.Unallocated, inventory.Discovered))
+	sel := nodeSelector{hostGroup: ansibleMasterGroupName, status: statusSel(inventory.Allocated)}
+	c.Assert(ns.count(sel), Equals, 0)
+
+	// the indexes are not updated until the node is reindexed
+	n, _ := ns.get("node1")
+	n.Cfg.(*configuration.AnsibleHost).SetGroup(ansibleMasterGroupName)
+	n.Inv = &fakeAsset{status: inventory.Allocated, state: inventory.Discovered}
+	c.Assert(ns.count(sel), Equals, 0)
+	ns.reindex("node1")
+	c.Assert(ns.selectNames(sel), DeepEquals, []string{"node1"})
+	c.Assert(ns.count(nodeSelector{status: statusSel(inventory.Unallocated)}), Equals, 0)
+
+	// replacing a node updates the indexes
+	ns.add("node1", newTestNode("node1", ansibleWorkerGroupName, inventory.Allocated, inventory.Discovered))
+	c.Assert(ns.count(sel), Equals, 0)
+	c.Assert(ns.count(nodeSelector{hostGroup: ansibleWorkerGroupName}), Equals, 1)
+	c.Assert(ns.len(), Equals, 1)
+
+	// reindexing an unknown node is a no-op
+	ns.reindex("node2")
+	c.Assert(ns.len(), Equals, 1)
+}
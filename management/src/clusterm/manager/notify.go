@@ -0,0 +1,111 @@
+package manager
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/contiv/cluster/management/src/inventory"
+	"github.com/contiv/cluster/management/src/monitor"
+	"github.com/contiv/cluster/management/src/notify"
+)
+
+// notify sends the event to the notification channels, if configured
+func (m *Manager) notify(e *notify.Event) {
+	if m.notifier == nil {
+		return
+	}
+	m.notifier.Notify(e)
+}
+
+// jobHostGroup returns the host-group of the job's nodes, if all of them are in the
+// same host-group. Else it returns an empty string.
+func (m *Manager) jobHostGroup(j *Job) string {
+	group := ""
+	for i, name := range j.ctxt.nodes {
+		n, err := m.findNode(name)
+		if err != nil || n.Cfg == nil || (i > 0 && n.Cfg.GetGroup() != group) {
+			return ""
+		}
+		group = n.Cfg.GetGroup()
+	}
+	return group
+}
+
+// notifyJobDone sends the job failure or completion event, as per the job's status
+func (m *Manager) notifyJobDone(j *Job) {
+	e := &notify.Event{
+		Type:      notify.JobCompleted,
+		Job:       j.desc,
+		HostGroup: m.jobHostGroup(j),
+		Details: map[string]string{
+			"job_id":     j.id,
+			"event_type": j.ctxt.eventType,
+			"nodes":      strings.Join(j.ctxt.nodes, ","),
+		},
+	}
+	if status, errVal := j.Status(); status == Errored {
+		e.Type = notify.JobFailed
+		if errVal != nil {
+			e.Error = errVal.Error()
+		}
+	}
+	m.notify(e)
+}
+
+// isCommissionedNode returns true if the node is in allocated status in inventory
+func (m *Manager) isCommissionedNode(name string) bool {
+	n, err := m.findNode(name)
+	if err != nil || n.Inv == nil {
+		return false
+	}
+	status, _ := n.Inv.GetStatus()
+	return status == inventory.Allocated
+}
+
+// watchNodeDown schedules a check for the node being down beyond the configured
+// threshold, if notifications are configured. The check is enqueued as an event so
+// that it's processed in the event loop.
+func (m *Manager) watchNodeDown(name string, mon monitor.SubsysNode) {
+	if m.notifier == nil || !m.isCommissionedNode(name) {
+		return
+	}
+	threshold := time.Duration(m.config.Notifications.NodeDownThresholdSecs) * time.Second
+	time.AfterFunc(threshold, func() {
+		m.reqQ <- newNodeDownEvent(m, name, mon, threshold)
+	})
+}
+
+// checkQuorumRisk sends the quorum risk event if the specified master node's
+// disappearance puts the quorum of commissioned masters at risk
+func (m *Manager) checkQuorumRisk(name string) {
+	if m.notifier == nil || !m.isCommissionedNode(name) {
+		return
+	}
+	if isMaster, err := m.isMasterNode(name); err != nil || !isMaster {
+		return
+	}
+
+	masters := m.nodes.count(nodeSelector{
+		hostGroup: ansibleMasterGroupName,
+		status:    statusSel(inventory.Allocated),
+	})
+	mastersUp := len(m.discoveredAndAllocatedNodes(ansibleMasterGroupName))
+
+	quorum := masters/2 + 1
+	if mastersUp > quorum {
+		return
+	}
+	m.notify(&notify.Event{
+		Type:      notify.QuorumRisk,
+		Node:      name,
+		HostGroup: ansibleMasterGroupName,
+		Details: map[string]string{
+			"masters":     strconv.Itoa(masters),
+			"masters_up":  strconv.Itoa(mastersUp),
+			"quorum":      strconv.Itoa(quorum),
+			"quorum_lost": fmt.Sprintf("%t", mastersUp < quorum),
+		},
+	})
+}
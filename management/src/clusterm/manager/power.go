@@ -0,0 +1,101 @@
+package manager
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/contiv/cluster/management/src/clustererr"
+	"github.com/contiv/cluster/management/src/inventory"
+	"github.com/contiv/cluster/management/src/power"
+)
+
+func errPowerNotConfigured() error {
+	return clustererr.New(clustererr.Validation, "power control of nodes is not configured, please add the power configuration to clusterm")
+}
+
+// nodeBMC returns the BMC info of a node as found in it's inventory attributes
+func (m *Manager) nodeBMC(name string) (*power.BMC, error) {
+	asset := m.inventory.GetAsset(name)
+	if asset == nil {
+		return nil, nodeInventoryNotExistsError(name)
+	}
+	attrs := asset.GetAttributes()
+	bmc := &power.BMC{
+		Addr:     attrs[inventory.AttrBMCAddress],
+		User:     attrs[inventory.AttrBMCUser],
+		Password: attrs[inventory.AttrBMCPassword],
+	}
+	if err := bmc.Validate(); err != nil {
+		return nil, clustererr.NewForNode(clustererr.Validation, name, "the BMC info for node %q is not usable. Error: %v", name, err)
+	}
+	return bmc, nil
+}
+
+// nodesBMC returns the BMC info of the specified nodes. It returns error if
+// power control is not configured or the BMC info of any node is not usable.
+func (m *Manager) nodesBMC(names []string) (map[string]*power.BMC, error) {
+	if m.power == nil {
+		return nil, errPowerNotConfigured()
+	}
+	bmcs := map[string]*power.BMC{}
+	for _, name := range names {
+		bmc, err := m.nodeBMC(name)
+		if err != nil {
+			return nil, err
+		}
+		bmcs[name] = bmc
+	}
+	return bmcs, nil
+}
+
+// powerableNodesBMC returns the BMC info of the specified nodes that can be
+// power controlled. Unlike nodesBMC it skips the nodes whose BMC info is not usable.
+func (m *Manager) powerableNodesBMC(names []string) map[string]*power.BMC {
+	if m.power == nil {
+		return nil
+	}
+	bmcs := map[string]*power.BMC{}
+	for _, name := range names {
+		bmc, err := m.nodeBMC(name)
+		if err != nil {
+			logrus.Infof("node %q shall not be power controlled. Error: %v", name, err)
+			continue
+		}
+		bmcs[name] = bmc
+	}
+	return bmcs
+}
+
+type powerCallback func(bmc *power.BMC) error
+
+// powerNodes runs the power action on the specified nodes. It continues on
+// failures and returns an error listing the nodes where the action failed.
+func powerNodes(bmcs map[string]*power.BMC, action string, powerCb powerCallback, jobLogs io.Writer) error {
+	failedNodes := []string{}
+	for name, bmc := range bmcs {
+		fmt.Fprintf(jobLogs, "powering %s node %q through BMC %s\n", action, name, bmc)
+		if err := powerCb(bmc); err != nil {
+			logrus.Errorf("failed to power %s node %q. Error: %v", action, name, err)
+			fmt.Fprintf(jobLogs, "failed to power %s node %q. Error: %v\n", action, name, err)
+			failedNodes = append(failedNodes, name)
+		}
+	}
+	if len(failedNodes) > 0 {
+		return clustererr.New(clustererr.Internal, "failed to power %s one or more nodes. Failed nodes: %v", action, failedNodes)
+	}
+	return nil
+}
+
+// waitForBoot waits for the specified time for nodes to boot after power on.
+// It returns early with an error if the job is cancelled.
+func waitForBoot(bootWait time.Duration, cancelCh CancelChannel, jobLogs io.Writer) error {
+	fmt.Fprintf(jobLogs, "waiting %s for the node(s) to boot\n", bootWait)
+	select {
+	case <-cancelCh:
+		return errJobCancelled
+	case <-time.After(bootWait):
+		return nil
+	}
+}
@@ -0,0 +1,71 @@
+package manager
+
+import (
+	"github.com/contiv/cluster/management/src/clustererr"
+	"github.com/contiv/cluster/management/src/publisher"
+)
+
+// nodeInventoryStatus returns the node's current status and state in inventory. The
+// inventory is looked up for the latest status as the node's asset may not be updated yet
+func (m *Manager) nodeInventoryStatus(name string) (string, string) {
+	asset := m.inventory.GetAsset(name)
+	if asset == nil {
+		return "", ""
+	}
+	status, state := asset.GetStatus()
+	return status.String(), state.String()
+}
+
+// nodeChanged is called on a node's lifecycle event. It reindexes the node and
+// publishes the event and syncs the node to CMDB, if configured
+func (m *Manager) nodeChanged(t publisher.EventType, name string) {
+	m.nodes.reindex(name)
+	m.publishNodeEvent(t, name)
+	m.syncNodeToCMDB(name)
+}
+
+// publishNodeEvent publishes the node lifecycle event, if event publishing is configured
+func (m *Manager) publishNodeEvent(t publisher.EventType, name string) {
+	if m.publisher == nil {
+		return
+	}
+	info := &publisher.NodeInfo{Name: name}
+	if n, err := m.findNode(name); err == nil {
+		if n.Mon != nil {
+			info.Addr = n.Mon.GetMgmtAddress()
+		}
+		if n.Cfg != nil {
+			info.HostGroup = n.Cfg.GetGroup()
+		}
+	}
+	info.Status, info.State = m.nodeInventoryStatus(name)
+	e := publisher.NewEvent(t)
+	e.Node = info
+	m.publisher.Publish(e)
+}
+
+// publishJobEvent publishes the job lifecycle event, if event publishing is configured
+func (m *Manager) publishJobEvent(t publisher.EventType, j *Job) {
+	if m.publisher == nil {
+		return
+	}
+	status, errVal := j.Status()
+	if t == publisher.JobStarted {
+		// the event is published right before the job is run
+		status = Running
+	}
+	e := publisher.NewEvent(t)
+	e.Job = &publisher.JobInfo{
+		ID:        j.id,
+		Desc:      j.desc,
+		EventType: j.ctxt.eventType,
+		Nodes:     j.ctxt.nodes,
+		Commit:    j.ctxt.commit,
+		Status:    status.String(),
+	}
+	if errVal != nil {
+		e.Job.Error = errVal.Error()
+		e.Job.ErrorCode = string(clustererr.CodeOf(errVal))
+	}
+	m.publisher.Publish(e)
+}
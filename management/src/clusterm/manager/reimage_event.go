@@ -0,0 +1,118 @@
+package manager
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/contiv/cluster/management/src/bootstrap"
+	"github.com/contiv/cluster/management/src/clustererr"
+	"github.com/contiv/cluster/management/src/inventory"
+	"github.com/contiv/cluster/management/src/power"
+)
+
+// reimageEvent triggers the reimage workflow of bare-metal nodes. The nodes are
+// set to be installed on next network boot and are power cycled.
+type reimageEvent struct {
+	mgr       *Manager
+	nodeNames []string
+
+	_bnodes []*bootstrap.Node
+	_bmcs   map[string]*power.BMC
+}
+
+// newReimageEvent creates and returns reimageEvent
+func newReimageEvent(mgr *Manager, nodeNames []string) *reimageEvent {
+	return &reimageEvent{
+		mgr:       mgr,
+		nodeNames: nodeNames,
+	}
+}
+
+func (e *reimageEvent) String() string {
+	return fmt.Sprintf("reimageEvent: nodes: %v", e.nodeNames)
+}
+
+func (e *reimageEvent) process() error {
+	// err shouldn't be redefined below
+	var err error
+
+	err = e.mgr.checkAndSetActiveJob(
+		e.String(),
+		jobContext{eventType: "reimage", nodes: e.nodeNames},
+		e.reimageRunner,
+		func(status JobStatus, errRet error) {
+			if status == Errored {
+				logrus.Errorf("reimage job failed. Error: %v", errRet)
+			}
+		})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			e.mgr.resetActiveJob()
+		}
+	}()
+
+	// validate event data
+	if err = e.eventValidate(); err != nil {
+		return err
+	}
+
+	// set the nodes to be installed on next network boot
+	for _, n := range e._bnodes {
+		if err = e.mgr.bootstrap.SetStatus(n.MAC, bootstrap.Install); err != nil {
+			return err
+		}
+	}
+
+	// trigger node power cycle
+	go e.mgr.runActiveJob()
+
+	return nil
+}
+
+// eventValidate checks that the nodes are registered for bootstrap, can be
+// power controlled and are not in use in the cluster.
+func (e *reimageEvent) eventValidate() error {
+	if e.mgr.bootstrap == nil {
+		return errBootstrapNotConfigured()
+	}
+	if len(e.nodeNames) == 0 {
+		return clustererr.New(clustererr.Validation, "atleast one node should be specified")
+	}
+
+	var err error
+	if e._bmcs, err = e.mgr.nodesBMC(e.nodeNames); err != nil {
+		return err
+	}
+
+	bnodes := map[string]*bootstrap.Node{}
+	for _, n := range e.mgr.bootstrap.GetAllNodes() {
+		bnodes[n.Name] = n
+	}
+	e._bnodes = []*bootstrap.Node{}
+	for _, name := range e.nodeNames {
+		n, ok := bnodes[name]
+		if !ok {
+			return clustererr.NewForNode(clustererr.Validation, name, "node %q is not registered for bootstrap", name)
+		}
+
+		// a node that is part of the cluster needs to be decommissioned first
+		if enode, err := e.mgr.findNodeByMgmtAddr(n.Addr); err == nil && enode.Inv != nil {
+			status, _ := enode.Inv.GetStatus()
+			if status != inventory.Unallocated && status != inventory.Decommissioned {
+				return clustererr.NewForNode(clustererr.Conflict, name, "node %q is in %q status, it needs to be decommissioned before reimage",
+					enode.Inv.GetTag(), status)
+			}
+		}
+		e._bnodes = append(e._bnodes, n)
+	}
+	return nil
+}
+
+// reimageRunner is the job runner that power cycles the nodes to boot them into OS installer
+func (e *reimageEvent) reimageRunner(cancelCh CancelChannel, jobLogs io.Writer) error {
+	return powerNodes(e._bmcs, "cycle", e.mgr.power.PowerCycle, jobLogs)
+}
@@ -5,11 +5,12 @@ import (
 	"io"
 	"reflect"
 
-	"github.com/contiv/errored"
+	"github.com/contiv/cluster/management/src/clustererr"
 )
 
 func configChangeNotPermittedError(config string) error {
-	return errored.Errorf("%q configuration can't be changed. Only changes to ansible configuration are allowed.", config)
+	return clustererr.New(clustererr.Validation,
+		"%q configuration can't be changed. Only changes to ansible configuration are allowed.", config)
 }
 
 // setConfigEvent triggers the update to global configuration
@@ -38,6 +39,7 @@ func (e *setConfigEvent) process() error {
 	// run no other job get's enqueued and catches us in middle of things
 	err = e.mgr.checkAndSetActiveJob(
 		e.String(),
+		jobContext{eventType: "set_config"},
 		e.noopRunner,
 		func(status JobStatus, errRet error) { return })
 	if err != nil {
@@ -71,7 +73,10 @@ func (e *setConfigEvent) process() error {
 
 func (e *setConfigEvent) eventValidate() error {
 	// make sure we are only changing ansible related config.
-	// Changes to monitoring, inventory and manager config is not supported
+	// Changes to monitoring, inventory, manager, power, bootstrap, dns,
+	// loadbalancer, notifications, events, vault, cmdb,
+	// remote_logging, metrics, gitops, job_logs, monitor and configuration
+	// config is not supported
 
 	if !reflect.DeepEqual(e.config.Serf, e.mgr.config.Serf) {
 		return configChangeNotPermittedError("serf")
@@ -82,6 +87,48 @@ func (e *setConfigEvent) eventValidate() error {
 	if !reflect.DeepEqual(e.config.Manager, e.mgr.config.Manager) {
 		return configChangeNotPermittedError("manager")
 	}
+	if !reflect.DeepEqual(e.config.Power, e.mgr.config.Power) {
+		return configChangeNotPermittedError("power")
+	}
+	if !reflect.DeepEqual(e.config.Bootstrap, e.mgr.config.Bootstrap) {
+		return configChangeNotPermittedError("bootstrap")
+	}
+	if !reflect.DeepEqual(e.config.DNS, e.mgr.config.DNS) {
+		return configChangeNotPermittedError("dns")
+	}
+	if !reflect.DeepEqual(e.config.LoadBalancer, e.mgr.config.LoadBalancer) {
+		return configChangeNotPermittedError("loadbalancer")
+	}
+	if !reflect.DeepEqual(e.config.Notifications, e.mgr.config.Notifications) {
+		return configChangeNotPermittedError("notifications")
+	}
+	if !reflect.DeepEqual(e.config.Events, e.mgr.config.Events) {
+		return configChangeNotPermittedError("events")
+	}
+	if !reflect.DeepEqual(e.config.Vault, e.mgr.config.Vault) {
+		return configChangeNotPermittedError("vault")
+	}
+	if !reflect.DeepEqual(e.config.CMDB, e.mgr.config.CMDB) {
+		return configChangeNotPermittedError("cmdb")
+	}
+	if !reflect.DeepEqual(e.config.RemoteLogging, e.mgr.config.RemoteLogging) {
+		return configChangeNotPermittedError("remote_logging")
+	}
+	if !reflect.DeepEqual(e.config.Metrics, e.mgr.config.Metrics) {
+		return configChangeNotPermittedError("metrics")
+	}
+	if !reflect.DeepEqual(e.config.GitOps, e.mgr.config.GitOps) {
+		return configChangeNotPermittedError("gitops")
+	}
+	if !reflect.DeepEqual(e.config.JobLogs, e.mgr.config.JobLogs) {
+		return configChangeNotPermittedError("job_logs")
+	}
+	if !reflect.DeepEqual(e.config.Monitor, e.mgr.config.Monitor) {
+		return configChangeNotPermittedError("monitor")
+	}
+	if !reflect.DeepEqual(e.config.Configuration, e.mgr.config.Configuration) {
+		return configChangeNotPermittedError("configuration")
+	}
 
 	return nil
 }
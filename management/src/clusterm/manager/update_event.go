@@ -5,8 +5,10 @@ import (
 	"io"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/contiv/cluster/management/src/clustererr"
 	"github.com/contiv/cluster/management/src/configuration"
-	"github.com/contiv/errored"
+	"github.com/contiv/cluster/management/src/dns"
+	"github.com/contiv/cluster/management/src/lb"
 )
 
 // updateEvent triggers the upgrade workflow
@@ -15,9 +17,16 @@ type updateEvent struct {
 	nodeNames []string
 	extraVars string
 	hostGroup string
-
-	_hosts  configuration.SubsysHosts
-	_enodes map[string]*node
+	// commit is the gitops commit that the event applies, if any
+	commit string
+
+	_hosts           configuration.SubsysHosts
+	_enodes          map[string]*node
+	_dnsRecords      []*dns.Record
+	_staleDNSRecords []*dns.Record
+	_oldLBMembers    []*lb.Member
+	_lbMembers       []*lb.Member
+	_staleLBMembers  []*lb.Member
 }
 
 // newUpdateEvent creates and returns updateEvent
@@ -40,6 +49,7 @@ func (e *updateEvent) process() error {
 
 	err = e.mgr.checkAndSetActiveJob(
 		e.String(),
+		jobContext{eventType: "update", nodes: e.nodeNames, commit: e.commit},
 		e.updateRunner,
 		func(status JobStatus, errRet error) {
 			if status == Errored {
@@ -91,42 +101,21 @@ func (e *updateEvent) eventValidate() error {
 	}
 
 	if e.hostGroup != "" && !IsValidHostGroup(e.hostGroup) {
-		return errored.Errorf("invalid host-group specified: %q", e.hostGroup)
+		return clustererr.New(clustererr.Validation, "invalid host-group specified: %q", e.hostGroup)
 	}
 
 	// when workers are being configured, make sure that there is atleast one service-master
 	if e.hostGroup == ansibleWorkerGroupName {
 		masterCommissioned := false
-		for name := range e.mgr.nodes {
-			if _, ok := e._enodes[name]; ok {
-				// skip nodes in the event
-				continue
-			}
-
-			isDiscoveredAndAllocated, err := e.mgr.isDiscoveredAndAllocatedNode(name)
-			if err != nil || !isDiscoveredAndAllocated {
-				if err != nil {
-					logrus.Debugf("a node check failed for %q. Error: %s", name, err)
-				}
-				// skip hosts that are not yet provisioned or not in discovered state
-				continue
-			}
-
-			isMasterNode, err := e.mgr.isMasterNode(name)
-			if err != nil || !isMasterNode {
-				if err != nil {
-					logrus.Debugf("a node check failed for %q. Error: %s", name, err)
-				}
-				//skip the hosts that are not in master group
-				continue
+		for _, name := range e.mgr.discoveredAndAllocatedNodes(ansibleMasterGroupName) {
+			if _, ok := e._enodes[name]; !ok {
+				// found a master node, that is not in the event
+				masterCommissioned = true
+				break
 			}
-
-			// found a master node
-			masterCommissioned = true
-			break
 		}
 		if !masterCommissioned {
-			return errored.Errorf("Updating these nodes as worker will result in no master node in the cluster, make sure atleast one node is commissioned as master.")
+			return clustererr.New(clustererr.Validation, "Updating these nodes as worker will result in no master node in the cluster, make sure atleast one node is commissioned as master.")
 		}
 	}
 	return nil
@@ -134,33 +123,58 @@ func (e *updateEvent) eventValidate() error {
 
 // pepareInventory prepares the inventory for update event.
 func (e *updateEvent) pepareInventory() error {
+	// the DNS record names and load balancer pool membership depend on host-group,
+	// so note the records and members before the host-group changes
+	oldRecords := e.mgr.dnsRecords(e._enodes)
+	e._oldLBMembers = e.mgr.lbMembers(e._enodes)
+
 	hosts := []*configuration.AnsibleHost{}
-	for _, node := range e._enodes {
+	for name, node := range e._enodes {
 		host := node.Cfg.(*configuration.AnsibleHost)
 		if e.hostGroup != "" {
 			host.SetGroup(e.hostGroup)
 		}
+		// the host-group index is updated for the new host-group
+		e.mgr.nodes.reindex(name)
 		hosts = append(hosts, host)
 	}
 	e._hosts = hosts
 
+	e._dnsRecords = e.mgr.dnsRecords(e._enodes)
+	e._staleDNSRecords = staleDNSRecords(oldRecords, e._dnsRecords)
+	e._lbMembers = e.mgr.lbMembers(e._enodes)
+	e._staleLBMembers = staleLBMembers(e._oldLBMembers, e._lbMembers)
+
 	return nil
 }
 
 // updateRunner is the job runner that runs a cleanup playbook followed by provision playbook
 // on one or more nodes. In case of provision failure the cleanup playbook it run again.
+// The nodes are drained from load balancer pool before the cleanup. On success the
+// DNS records and pool membership of the nodes are updated, if configured. On failure
+// the nodes are removed from the pool.
 func (e *updateEvent) updateRunner(cancelCh CancelChannel, jobLogs io.Writer) error {
+	if err := e.mgr.drainLBMembers(e._oldLBMembers, cancelCh, jobLogs); err != nil {
+		e.mgr.deregisterLBMembers(e._oldLBMembers, jobLogs)
+		return err
+	}
 	outReader, cancelFunc, errCh := e.mgr.configuration.Cleanup(e._hosts, e.extraVars)
 	if err := logOutputAndReturnStatus(outReader, errCh, cancelCh, cancelFunc, jobLogs); err != nil {
 		logrus.Errorf("first cleanup failed. Error: %s", err)
+		e.mgr.deregisterLBMembers(e._oldLBMembers, jobLogs)
 		// XXX: is there a case where we should continue on error here?
 		return err
 	}
 	outReader, cancelFunc, errCh = e.mgr.configuration.Configure(e._hosts, e.extraVars)
 	cfgErr := logOutputAndReturnStatus(outReader, errCh, cancelCh, cancelFunc, jobLogs)
 	if cfgErr == nil {
+		e.mgr.removeDNSRecords(e._staleDNSRecords, jobLogs)
+		e.mgr.addDNSRecords(e._dnsRecords, jobLogs)
+		e.mgr.deregisterLBMembers(e._staleLBMembers, jobLogs)
+		e.mgr.registerLBMembers(e._lbMembers, jobLogs)
 		return nil
 	}
+	e.mgr.deregisterLBMembers(e._oldLBMembers, jobLogs)
 	logrus.Errorf("configuration failed, starting cleanup. Error: %s", cfgErr)
 	outReader, cancelFunc, errCh = e.mgr.configuration.Cleanup(e._hosts, e.extraVars)
 	if err := logOutputAndReturnStatus(outReader, errCh, cancelCh, cancelFunc, jobLogs); err != nil {
@@ -1,25 +1,39 @@
 package manager
 
 import (
+	"fmt"
+	"io"
+	"time"
+
 	"github.com/Sirupsen/logrus"
+	"github.com/contiv/cluster/management/src/clustererr"
 	"github.com/contiv/cluster/management/src/inventory"
-	"github.com/contiv/errored"
+	"github.com/contiv/cluster/management/src/monitor"
+	"github.com/contiv/cluster/management/src/publisher"
+	"github.com/contiv/cluster/management/src/remotelog"
+	"github.com/contiv/cluster/management/src/retry"
 )
 
 func nodeNotExistsError(nameOrAddr string) error {
-	return errored.Errorf("node with name or address %q doesn't exists", nameOrAddr)
+	return clustererr.NewForNode(clustererr.Validation, nameOrAddr,
+		"node with name or address %q doesn't exists", nameOrAddr)
 }
 
 func nodeConfigNotExistsError(name string) error {
-	return errored.Errorf("the configuration info for node %q doesn't exist", name)
+	return clustererr.NewForNode(clustererr.Validation, name, "the configuration info for node %q doesn't exist", name)
+}
+
+func nodeNotDiscoveredError(name string) error {
+	return clustererr.NewForNode(clustererr.Conflict, name,
+		"node %q is not in discovered state, please check it's network reachability", name)
 }
 
 func nodeInventoryNotExistsError(name string) error {
-	return errored.Errorf("the inventory info for node %q doesn't exist", name)
+	return clustererr.NewForNode(clustererr.Validation, name, "the inventory info for node %q doesn't exist", name)
 }
 
 func (m *Manager) findNode(name string) (*node, error) {
-	n, ok := m.nodes[name]
+	n, ok := m.nodes.get(name)
 	if !ok {
 		return nil, nodeNotExistsError(name)
 	}
@@ -27,12 +41,56 @@ func (m *Manager) findNode(name string) (*node, error) {
 }
 
 func (m *Manager) findNodeByMgmtAddr(addr string) (*node, error) {
-	for _, node := range m.nodes {
-		if node.Mon.GetMgmtAddress() == addr {
-			return node, nil
+	n, ok := m.nodes.getByAddr(addr)
+	if !ok {
+		return nil, nodeNotExistsError(addr)
+	}
+	return n, nil
+}
+
+// monitorNodeName returns the name of the node reported by monitoring subsystem
+func monitorNodeName(n monitor.SubsysNode) string {
+	//XXX: need to form the name that adheres to collins tag requirements
+	return n.GetLabel() + "-" + n.GetSerial()
+}
+
+// monitorNodeNames returns the names of the nodes reported by monitoring subsystem
+func monitorNodeNames(nodes []monitor.SubsysNode) []string {
+	names := []string{}
+	for _, n := range nodes {
+		names = append(names, monitorNodeName(n))
+	}
+	return names
+}
+
+// splitBatchErrors records the per node errors of a batched inventory update
+// in errs and returns the names of the nodes that were updated
+func splitBatchErrors(names []string, err error, errs nodeErrors) []string {
+	if err == nil {
+		return names
+	}
+	assetErrs, ok := err.(inventory.AssetErrors)
+	updated := []string{}
+	for _, name := range names {
+		if !ok {
+			errs[name] = clustererr.Wrap(clustererr.Inventory, name, err)
+		} else if assetErr, failed := assetErrs[name]; failed {
+			errs[name] = clustererr.Wrap(clustererr.Inventory, name, assetErr)
+		} else {
+			updated = append(updated, name)
 		}
 	}
-	return nil, nodeNotExistsError(addr)
+	return updated
+}
+
+// discoveredAndAllocatedNodes returns the names of the nodes in the host-group
+// that are discovered and commissioned
+func (m *Manager) discoveredAndAllocatedNodes(hostGroup string) []string {
+	return m.nodes.selectNames(nodeSelector{
+		hostGroup: hostGroup,
+		status:    statusSel(inventory.Allocated),
+		state:     stateSel(inventory.Discovered),
+	})
 }
 
 func (m *Manager) isMasterNode(name string) (bool, error) {
@@ -69,25 +127,6 @@ func (m *Manager) isDiscoveredNode(name string) (bool, error) {
 	return state == inventory.Discovered, nil
 }
 
-// areDiscovered checks if all nodes are in discovered state.
-// Returns nil error if all nodes are discovered, else returns appropriate error
-func (m *Manager) areDiscoveredNodes(names []string) error {
-	disappearedNodes := []string{}
-	for _, name := range names {
-		discovered, err := m.isDiscoveredNode(name)
-		if err != nil {
-			return err
-		}
-		if !discovered {
-			disappearedNodes = append(disappearedNodes, name)
-		}
-	}
-	if len(disappearedNodes) > 0 {
-		return errored.Errorf("one or more nodes are not in discovered state, please check their network reachability. Non-discovered nodes: %v", disappearedNodes)
-	}
-	return nil
-}
-
 func (m *Manager) isDiscoveredAndAllocatedNode(name string) (bool, error) {
 	n, err := m.findNode(name)
 	if err != nil {
@@ -102,34 +141,110 @@ func (m *Manager) isDiscoveredAndAllocatedNode(name string) (bool, error) {
 
 type setInvStateCallback func(name string) error
 
+const (
+	// eventLoopRetryAttempts and eventLoopRetryMaxBackoffMsecs cap the retries of
+	// the inventory calls made from the event loop, as the queued events wait on them
+	eventLoopRetryAttempts        = 2
+	eventLoopRetryMaxBackoffMsecs = 100
+)
+
+// eventLoopRetryPolicy returns the inventory's retry policy, capped for the calls
+// made from the event loop
+func (m *Manager) eventLoopRetryPolicy() retry.Policy {
+	policy := m.config.Inventory.Retry
+	if policy.MaxAttempts <= 0 || policy.MaxAttempts > eventLoopRetryAttempts {
+		policy.MaxAttempts = eventLoopRetryAttempts
+	}
+	if policy.MaxBackoffMsecs > eventLoopRetryMaxBackoffMsecs {
+		policy.MaxBackoffMsecs = eventLoopRetryMaxBackoffMsecs
+	}
+	return policy
+}
+
+// retryInventory calls fn as per the retry policy, retrying it on the failures
+// of the backend. The retries are logged and also written to logs, if it is not nil.
+func (m *Manager) retryInventory(desc string, logs io.Writer, policy retry.Policy, fn func() error) error {
+	r := retry.Retrier{
+		Policy:    policy,
+		Retriable: retry.IsBackendError,
+		OnRetry: func(attempt int, backoff time.Duration, err error) {
+			msg := fmt.Sprintf("retrying %s in %s, attempt %d", desc, backoff, attempt)
+			if policy.MaxAttempts > 0 {
+				msg = fmt.Sprintf("%s of %d", msg, policy.MaxAttempts)
+			}
+			msg = fmt.Sprintf("%s. Last error: %v", msg, err)
+			logrus.Warnf("%s", msg)
+			if logs != nil {
+				fmt.Fprintln(logs, msg)
+			}
+		},
+	}
+	return r.Do(fn)
+}
+
+// activeJobLogs returns the writer of the active job's logs, if there is one
+func (m *Manager) activeJobLogs() io.Writer {
+	if m.activeJob == nil {
+		return nil
+	}
+	return m.activeJob.logWriter
+}
+
+// setAssetStatus sets the status of an asset in inventory, retrying on the
+// failures of the backend as part of the active job
+func (m *Manager) setAssetStatus(name string, newStatusCb setInvStateCallback, policy retry.Policy) error {
+	return m.retryInventory(fmt.Sprintf("update of %s's state in inventory", name), m.activeJobLogs(), policy,
+		func() error { return newStatusCb(name) })
+}
+
 // tries to set the newStatus as state of all assets, it continues on failures
 func (m *Manager) setAssetsStatusBestEffort(names []string, newStatusCb setInvStateCallback) {
+	m.setAssetsStatusWithPolicy(names, newStatusCb, m.config.Inventory.Retry)
+}
+
+// setAssetsStatusWithPolicy is like setAssetsStatusBestEffort, with the updates
+// retried as per the policy
+func (m *Manager) setAssetsStatusWithPolicy(names []string, newStatusCb setInvStateCallback, policy retry.Policy) {
 	for _, name := range names {
-		if err := newStatusCb(name); err != nil {
+		if err := m.setAssetStatus(name, newStatusCb, policy); err != nil {
 			logrus.Errorf("failed to update %s's state in inventory, Error: %v", name, err)
 			continue
 		}
+		m.nodeChanged(publisher.NodeStatusChanged, name)
 	}
 }
 
-// try to atomically set the newStatus as state of all assets or revert to revertStatus in case of failure
+// try to atomically set the newStatus as state of all assets or revert to revertStatus in case of failure.
+// It's called from the event loop, so the updates are retried as per the capped policy
 func (m *Manager) setAssetsStatusAtomic(names []string, newStatusCb setInvStateCallback, revertStatusCb setInvStateCallback) error {
+	policy := m.eventLoopRetryPolicy()
 	for i, name := range names {
-		if err := newStatusCb(name); err != nil {
+		if err := m.setAssetStatus(name, newStatusCb, policy); err != nil {
 			// try to revert back to original state in case of failure
-			m.setAssetsStatusBestEffort(names[0:i+1], revertStatusCb)
-			return errored.Errorf("failed to update %s's state in inventory, Error: %v", name, err)
+			m.setAssetsStatusWithPolicy(names[0:i+1], revertStatusCb, policy)
+			return clustererr.Wrapf(clustererr.Inventory, name, err, "failed to update %s's state in inventory, Error: %v",
+				name, err)
 		}
+		m.nodeChanged(publisher.NodeStatusChanged, name)
 	}
 	return nil
 }
 
 // checkAndGetNewJob() is a wrapper to check that there are no active jobs before a job is run
-func (m *Manager) checkAndSetActiveJob(jobDesc string, runner JobRunner, doneCb DoneCallback) error {
+func (m *Manager) checkAndSetActiveJob(jobDesc string, ctxt jobContext, runner JobRunner, doneCb DoneCallback) error {
 	if m.activeJob != nil {
 		return errActiveJob(m.activeJob.String())
 	}
-	m.activeJob = NewJob(jobDesc, runner, doneCb)
+	id := newJobID()
+	m.activeJob = newJob(id, jobDesc, runner, doneCb, newJobLog(m.config.JobLogs, id))
+	m.activeJob.ctxt = ctxt
+	if m.logShipper != nil {
+		m.activeJob.logWriter.Add(remotelog.NewJobWriter(m.logShipper, m.activeJob.id, ctxt.eventType,
+			ctxt.nodes))
+	}
+	if ctxt.commit != "" {
+		fmt.Fprintf(m.activeJob.logWriter, "applying gitops commit: %s\n", ctxt.commit)
+	}
 	return nil
 }
 
@@ -147,9 +262,23 @@ func (m *Manager) runActiveJob() {
 		logrus.Errorf("run called without an active job")
 		return
 	}
+	m.publishJobEvent(publisher.JobStarted, m.activeJob)
+	start := time.Now()
 	m.activeJob.Run()
+	m.emitJobMetrics(m.activeJob, time.Since(start))
+	if status, _ := m.activeJob.Status(); status == Errored {
+		m.publishJobEvent(publisher.JobFailed, m.activeJob)
+	} else {
+		m.publishJobEvent(publisher.JobCompleted, m.activeJob)
+	}
+	m.notifyJobDone(m.activeJob)
 	// reset the active job once done
+	job := m.activeJob
 	m.resetActiveJob()
+	if job.ctxt.commit != "" {
+		// signal the gitops loop waiting on the job
+		m.gitopsJobDone <- job
+	}
 }
 
 // IsValidHostGroup checks if the passed hostGroup is valid
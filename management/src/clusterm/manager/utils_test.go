@@ -3,6 +3,14 @@
 package manager
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/contiv/cluster/management/src/clustererr"
+	"github.com/contiv/cluster/management/src/configuration"
+	"github.com/contiv/cluster/management/src/inventory"
+	"github.com/contiv/cluster/management/src/retry"
 	"github.com/contiv/errored"
 	. "gopkg.in/check.v1"
 )
@@ -33,7 +41,7 @@ func (s *eventUtilsSuite) TestSetStatusAtomicSuccess(c *C) {
 	strs := []string{"foo", "bar", "dead", "beef"}
 	setStrs := []string{}
 	revertStrs := []string{}
-	mgr := &Manager{}
+	mgr := &Manager{config: DefaultConfig(), nodes: newNodeStore()}
 	mgr.setAssetsStatusAtomic(strs, recordCb(&setStrs), recordCb(&revertStrs))
 	c.Assert(strs, DeepEquals, setStrs)
 	c.Assert(len(revertStrs), Equals, 0)
@@ -43,7 +51,7 @@ func (s *eventUtilsSuite) TestSetStatusAtomicFailure(c *C) {
 	strs := []string{"foo", "bar", "dead", "beef", "test", "blah"}
 	setStrs := []string{}
 	revertStrs := []string{}
-	mgr := &Manager{}
+	mgr := &Manager{config: DefaultConfig(), nodes: newNodeStore()}
 	mgr.setAssetsStatusAtomic(strs, failureCb(&setStrs, 2), recordCb(&revertStrs))
 	c.Assert(len(setStrs), Equals, 2)
 	c.Assert(setStrs, DeepEquals, revertStrs)
@@ -52,7 +60,7 @@ func (s *eventUtilsSuite) TestSetStatusAtomicFailure(c *C) {
 func (s *eventUtilsSuite) TestSetStatusBestEffortSuccess(c *C) {
 	strs := []string{"foo", "bar", "dead", "beef"}
 	setStrs := []string{}
-	mgr := &Manager{}
+	mgr := &Manager{config: DefaultConfig(), nodes: newNodeStore()}
 	mgr.setAssetsStatusBestEffort(strs, recordCb(&setStrs))
 	c.Assert(strs, DeepEquals, setStrs)
 }
@@ -60,7 +68,168 @@ func (s *eventUtilsSuite) TestSetStatusBestEffortSuccess(c *C) {
 func (s *eventUtilsSuite) TestSetStatusBestEffortFailure(c *C) {
 	strs := []string{"foo", "bar", "dead", "beef", "test", "blah"}
 	setStrs := []string{}
-	mgr := &Manager{}
+	mgr := &Manager{config: DefaultConfig(), nodes: newNodeStore()}
 	mgr.setAssetsStatusBestEffort(strs, failureCb(&setStrs, 2))
 	c.Assert(strs, DeepEquals, setStrs)
 }
+
+// transientFailureCb fails with an inventory error for the first failures calls
+// of every name
+func transientFailureCb(strs *[]string, failures int) setInvStateCallback {
+	calls := map[string]int{}
+	return func(name string) error {
+		*strs = append(*strs, name)
+		if calls[name]++; calls[name] <= failures {
+			return clustererr.NewForNode(clustererr.Inventory, name, "connection refused")
+		}
+		return nil
+	}
+}
+
+func (s *eventUtilsSuite) TestSetStatusAtomicRetry(c *C) {
+	strs := []string{"foo", "bar"}
+	setStrs := []string{}
+	revertStrs := []string{}
+	mgr := &Manager{config: DefaultConfig(), nodes: newNodeStore()}
+	mgr.config.Inventory.Retry = retry.Policy{MaxAttempts: 3, InitialBackoffMsecs: 1, MaxBackoffMsecs: 1}
+	c.Assert(mgr.setAssetsStatusAtomic(strs, transientFailureCb(&setStrs, 1), recordCb(&revertStrs)), IsNil)
+	c.Assert(setStrs, DeepEquals, []string{"foo", "foo", "bar", "bar"})
+	c.Assert(len(revertStrs), Equals, 0)
+
+	// the status is reverted once the attempts, capped for the event loop, are exhausted
+	setStrs = []string{}
+	err := mgr.setAssetsStatusAtomic(strs, transientFailureCb(&setStrs, 2), recordCb(&revertStrs))
+	c.Assert(err, ErrorMatches, "failed to update foo's state.*giving up after 2 attempt\\(s\\). Last error: connection refused")
+	c.Assert(clustererr.CodeOf(err), Equals, clustererr.Inventory)
+	c.Assert(clustererr.NodeOf(err), Equals, "foo")
+	c.Assert(setStrs, DeepEquals, []string{"foo", "foo"})
+	c.Assert(revertStrs, DeepEquals, []string{"foo"})
+}
+
+func (s *eventUtilsSuite) TestSetStatusBestEffortRetry(c *C) {
+	strs := []string{"foo", "bar"}
+	setStrs := []string{}
+	mgr := &Manager{config: DefaultConfig(), nodes: newNodeStore()}
+	mgr.config.Inventory.Retry = retry.Policy{MaxAttempts: 3, InitialBackoffMsecs: 1, MaxBackoffMsecs: 1}
+	// the best effort updates are made off the event loop, with the full policy
+	mgr.setAssetsStatusBestEffort(strs, transientFailureCb(&setStrs, 2))
+	c.Assert(setStrs, DeepEquals, []string{"foo", "foo", "foo", "bar", "bar", "bar"})
+}
+
+func (s *eventUtilsSuite) TestEventLoopRetryPolicy(c *C) {
+	mgr := &Manager{config: DefaultConfig()}
+	mgr.config.Inventory.Retry = retry.Policy{MaxAttempts: 0, InitialBackoffMsecs: 50, MaxBackoffMsecs: 60000, Jitter: 0.2}
+	c.Assert(mgr.eventLoopRetryPolicy(), DeepEquals, retry.Policy{MaxAttempts: eventLoopRetryAttempts,
+		InitialBackoffMsecs: 50, MaxBackoffMsecs: eventLoopRetryMaxBackoffMsecs, Jitter: 0.2})
+
+	// a tighter policy is retained
+	mgr.config.Inventory.Retry = retry.Policy{MaxAttempts: 1, InitialBackoffMsecs: 1, MaxBackoffMsecs: 10}
+	c.Assert(mgr.eventLoopRetryPolicy(), DeepEquals, mgr.config.Inventory.Retry)
+}
+
+func (s *eventUtilsSuite) TestRetryInventoryLogs(c *C) {
+	mgr := &Manager{config: DefaultConfig()}
+	policy := retry.Policy{MaxAttempts: 2, InitialBackoffMsecs: 1, MaxBackoffMsecs: 1}
+	logs := &bytes.Buffer{}
+	attempts := 0
+	err := mgr.retryInventory("update of foo", logs, policy, func() error {
+		attempts++
+		return clustererr.New(clustererr.Inventory, "connection refused")
+	})
+	c.Assert(err, ErrorMatches, "giving up after 2 attempt\\(s\\). Last error: connection refused")
+	c.Assert(attempts, Equals, 2)
+	c.Assert(logs.String(), Equals,
+		"retrying update of foo in 1ms, attempt 2 of 2. Last error: connection refused\n")
+
+	// the conflicts are not retried
+	logs.Reset()
+	attempts = 0
+	err = mgr.retryInventory("update of foo", logs, policy, func() error {
+		attempts++
+		return clustererr.New(clustererr.Conflict, "transition not allowed")
+	})
+	c.Assert(err, ErrorMatches, "transition not allowed")
+	c.Assert(attempts, Equals, 1)
+	c.Assert(logs.String(), Equals, "")
+}
+
+// fakeAsset is an inventory asset with a fixed status and state
+type fakeAsset struct {
+	status inventory.AssetStatus
+	state  inventory.AssetState
+}
+
+func (a *fakeAsset) GetStatus() (inventory.AssetStatus, inventory.AssetState) {
+	return a.status, a.state
+}
+
+func (a *fakeAsset) GetTag() string {
+	return ""
+}
+
+func (a *fakeAsset) GetAttributes() map[string]string {
+	return nil
+}
+
+func (a *fakeAsset) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct{}{})
+}
+
+func (s *eventUtilsSuite) TestForEachNode(c *C) {
+	mgr := &Manager{config: DefaultConfig()}
+	names := []string{}
+	for i := 0; i < 10; i++ {
+		names = append(names, fmt.Sprintf("node%d", i))
+	}
+
+	visited := []string{}
+	err := mgr.forEachNode(names, func(name string) error {
+		visited = append(visited, name)
+		if name == "node3" || name == "node7" {
+			return errored.Errorf("test failure")
+		}
+		return nil
+	})
+	c.Assert(visited, DeepEquals, names)
+	c.Assert(err, FitsTypeOf, nodeErrors{})
+	c.Assert(err.(nodeErrors), HasLen, 2)
+	c.Assert(err, ErrorMatches, `2 node\(s\) failed. Errors: \[node3: .*test failure.*; node7: .*test failure.*\]`)
+
+	c.Assert(mgr.forEachNode(names, func(name string) error { return nil }), IsNil)
+}
+
+func (s *eventUtilsSuite) TestCommonEventValidate(c *C) {
+	mgr := &Manager{config: DefaultConfig(), nodes: newNodeStore()}
+	for _, n := range []struct {
+		name  string
+		state inventory.AssetState
+	}{
+		{"node1", inventory.Discovered},
+		{"node2", inventory.Discovered},
+		{"node3", inventory.Disappeared},
+	} {
+		mgr.nodes.add(n.name, &node{
+			Inv: &fakeAsset{status: inventory.Unallocated, state: n.state},
+			Cfg: configuration.NewAnsibleHost(n.name, "", "", nil),
+		})
+	}
+	mgr.nodes.add("node4", &node{Inv: &fakeAsset{status: inventory.Unallocated, state: inventory.Discovered}})
+
+	enodes, err := mgr.commonEventValidate([]string{"node1", "node2"})
+	c.Assert(err, IsNil)
+	node1, _ := mgr.nodes.get("node1")
+	node2, _ := mgr.nodes.get("node2")
+	c.Assert(enodes, DeepEquals, map[string]*node{"node1": node1, "node2": node2})
+
+	// the errors of all the failing nodes are returned
+	_, err = mgr.commonEventValidate([]string{"node1", "node3", "node4", "node5"})
+	c.Assert(err, FitsTypeOf, nodeErrors{})
+	errs := err.(nodeErrors)
+	c.Assert(errs, HasLen, 3)
+	c.Assert(errs["node3"], ErrorMatches, ".*not in discovered state.*")
+	c.Assert(errs["node4"], ErrorMatches, ".*configuration info for node \"node4\" doesn't exist.*")
+	c.Assert(errs["node5"], ErrorMatches, ".*doesn't exists.*")
+
+	_, err = mgr.commonEventValidate([]string{})
+	c.Assert(err, ErrorMatches, ".*atleast one node should be specified.*")
+}
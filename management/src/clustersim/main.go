@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/codegangsta/cli"
+	"github.com/contiv/cluster/management/src/clusterm/manager"
+	"github.com/contiv/cluster/management/src/simulator"
+)
+
+// version is provided by build
+var version = ""
+
+func main() {
+	app := cli.NewApp()
+	app.Name = os.Args[0]
+	app.Usage = "runs a cluster manager for a cluster of simulated nodes"
+	app.Version = version
+	app.Flags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "addr, a",
+			Value: manager.DefaultConfig().Manager.Addr,
+			Usage: "address the cluster manager's REST service listens on",
+		},
+		cli.IntFlag{
+			Name:  "nodes, n",
+			Value: 10,
+			Usage: "number of simulated nodes",
+		},
+		cli.IntFlag{
+			Name:  "step-msecs, s",
+			Value: 1000,
+			Usage: "time in milliseconds it takes to simulate a configuration action on a node",
+		},
+		cli.StringSliceFlag{
+			Name:  "fail-node, f",
+			Value: &cli.StringSlice{},
+			Usage: "name of a node, the configuration of which fails. Can be repeated",
+		},
+		cli.BoolFlag{
+			Name:  "debug, d",
+			Usage: "enable debug logs",
+		},
+	}
+	app.Action = startCluster
+
+	app.Run(os.Args)
+}
+
+func startCluster(c *cli.Context) {
+	if c.GlobalBool("debug") {
+		logrus.SetLevel(logrus.DebugLevel)
+	}
+	logrus.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+
+	cl := simulator.NewCluster(simulator.Config{
+		Addr:      c.GlobalString("addr"),
+		Nodes:     c.GlobalInt("nodes"),
+		StepMsecs: c.GlobalInt("step-msecs"),
+		FailNodes: c.GlobalStringSlice("fail-node"),
+	})
+	errCh, err := cl.Start()
+	if err != nil {
+		logrus.Fatalf("failed to start the simulated cluster. Error: %v", err)
+	}
+	logrus.Infof("simulated cluster with %d node(s) is running. Use `clusterctl --url %s` to manage it",
+		len(cl.NodeNames()), cl.Addr())
+
+	select {
+	case err := <-errCh:
+		logrus.Fatalf("encountered an error: %s", err)
+	}
+}
@@ -0,0 +1,205 @@
+package cmdb
+
+import (
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/contiv/cluster/management/src/clustererr"
+	"github.com/contiv/cluster/management/src/retry"
+	"github.com/contiv/errored"
+)
+
+// the node fields that can be mapped to the CMDB fields
+const (
+	FieldName      = "name"
+	FieldAddr      = "addr"
+	FieldHostGroup = "host_group"
+	FieldStatus    = "status"
+	FieldState     = "state"
+	FieldSerial    = "serial"
+	FieldLabel     = "label"
+)
+
+var nodeFields = []string{FieldName, FieldAddr, FieldHostGroup, FieldStatus, FieldState, FieldSerial, FieldLabel}
+
+// Node denotes the node info that is synced to the CMDB
+type Node struct {
+	Name      string
+	Addr      string
+	HostGroup string
+	Status    string
+	State     string
+	Serial    string
+	Label     string
+}
+
+func (n *Node) field(name string) string {
+	switch name {
+	case FieldName:
+		return n.Name
+	case FieldAddr:
+		return n.Addr
+	case FieldHostGroup:
+		return n.HostGroup
+	case FieldStatus:
+		return n.Status
+	case FieldState:
+		return n.State
+	case FieldSerial:
+		return n.Serial
+	case FieldLabel:
+		return n.Label
+	}
+	return ""
+}
+
+// FieldMap maps the CMDB fields to the node fields, like {"ip_address": "addr"}
+type FieldMap map[string]string
+
+// Validate returns error if a CMDB field is mapped to an unknown node field
+func (m FieldMap) Validate() error {
+	for cmdbField, nodeField := range m {
+		known := false
+		for _, f := range nodeFields {
+			if f == nodeField {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return errored.Errorf("cmdb field %q is mapped to an unknown node field %q, valid node fields are %v",
+				cmdbField, nodeField, nodeFields)
+		}
+	}
+	return nil
+}
+
+// Record returns the CMDB record of the node as per the field mapping
+func (m FieldMap) Record(n *Node) map[string]string {
+	r := map[string]string{}
+	for cmdbField, nodeField := range m {
+		r[cmdbField] = n.field(nodeField)
+	}
+	return r
+}
+
+// Subsys provides the following services to the cluster manager:
+// - Interface to sync the node info to a configuration management database (CMDB).
+type Subsys interface {
+	// Sync enqueues the node for syncing to the CMDB. It doesn't block on syncing
+	// the node and the failures are only logged.
+	Sync(n *Node)
+}
+
+// Driver is implemented by the CMDB specific drivers
+type Driver interface {
+	// Upsert creates or updates the record of the node in the CMDB
+	Upsert(name string, record map[string]string) error
+}
+
+// RetryConfig denotes the retry behavior on failure to sync a node
+type RetryConfig struct {
+	// MaxAttempts is the max times a sync is attempted, including the first one.
+	// The sync is attempted once when it is not set
+	MaxAttempts int `json:"max_attempts"`
+	// InitialBackoffSecs is the time to wait before the first retry, it is doubled
+	// for every subsequent retry upto MaxBackoffSecs
+	InitialBackoffSecs int `json:"initial_backoff_secs"`
+	MaxBackoffSecs     int `json:"max_backoff_secs"`
+}
+
+// DefaultRetryConfig returns the default retry configuration
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts:        5,
+		InitialBackoffSecs: 1,
+		MaxBackoffSecs:     60,
+	}
+}
+
+// policy returns the retry policy for the configuration. Unlike the policy, a
+// sync is not retried forever when MaxAttempts is not set
+func (c RetryConfig) policy() retry.Policy {
+	attempts := c.MaxAttempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+	return retry.Policy{
+		MaxAttempts:         attempts,
+		InitialBackoffMsecs: c.InitialBackoffSecs * 1000,
+		MaxBackoffMsecs:     c.MaxBackoffSecs * 1000,
+	}
+}
+
+// backoff returns the time to wait before the specified retry
+func (c RetryConfig) backoff(retry int) time.Duration {
+	return c.policy().Backoff(retry)
+}
+
+// queueSize is the number of syncs that can be pending before the newer ones are dropped
+const queueSize = 1000
+
+// AsyncSyncer implements the CMDB subsystem by syncing the nodes through the driver,
+// in the order they are enqueued
+type AsyncSyncer struct {
+	driver   Driver
+	fieldMap FieldMap
+	retry    RetryConfig
+	queue    chan *Node
+	sleep    func(time.Duration)
+}
+
+// NewAsyncSyncer initializes and returns an instance of CMDB subsystem
+func NewAsyncSyncer(driver Driver, fieldMap FieldMap, retry RetryConfig) (*AsyncSyncer, error) {
+	if err := fieldMap.Validate(); err != nil {
+		return nil, err
+	}
+	s := &AsyncSyncer{
+		driver:   driver,
+		fieldMap: fieldMap,
+		retry:    retry,
+		queue:    make(chan *Node, queueSize),
+		sleep:    time.Sleep,
+	}
+	go s.syncLoop()
+	return s, nil
+}
+
+func (s *AsyncSyncer) syncLoop() {
+	for n := range s.queue {
+		if err := s.sync(n); err != nil {
+			logrus.Errorf("failed to sync node %q to cmdb. Error: %v", n.Name, err)
+		}
+	}
+}
+
+// isRetriable returns true if a failed sync shall be retried. A record that the
+// driver rejects as invalid would fail the same way again
+func isRetriable(err error) bool {
+	return clustererr.CodeOf(err) != clustererr.Validation
+}
+
+// sync upserts the node's record, retrying with exponential backoff on failure
+func (s *AsyncSyncer) sync(n *Node) error {
+	record := s.fieldMap.Record(n)
+	policy := s.retry.policy()
+	r := retry.Retrier{
+		Policy:    policy,
+		Retriable: isRetriable,
+		OnRetry: func(attempt int, backoff time.Duration, err error) {
+			logrus.Infof("retrying sync of node %q to cmdb in %s, attempt %d of %d. Last error: %v",
+				n.Name, backoff, attempt, policy.MaxAttempts, err)
+		},
+		Sleep: s.sleep,
+	}
+	return r.Do(func() error { return s.driver.Upsert(n.Name, record) })
+}
+
+// Sync implements the sync interface of CMDB subsystem
+func (s *AsyncSyncer) Sync(n *Node) {
+	select {
+	case s.queue <- n:
+	default:
+		logrus.Errorf("cmdb sync queue is full, dropping sync of node %q", n.Name)
+	}
+}
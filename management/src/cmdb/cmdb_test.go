@@ -0,0 +1,173 @@
+// +build unittest
+
+package cmdb
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/contiv/cluster/management/src/clustererr"
+	"github.com/contiv/errored"
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type cmdbSuite struct {
+}
+
+var _ = Suite(&cmdbSuite{})
+
+var testNode = &Node{
+	Name:      "node1-serial1",
+	Addr:      "1.2.3.4",
+	HostGroup: "service-master",
+	Status:    "Allocated",
+	State:     "Discovered",
+	Serial:    "serial1",
+	Label:     "node1",
+}
+
+func (s *cmdbSuite) TestFieldMap(c *C) {
+	m := FieldMap{"hostname": FieldName, "ip": FieldAddr}
+	c.Assert(m.Validate(), IsNil)
+	c.Assert(m.Record(testNode), DeepEquals, map[string]string{"hostname": "node1-serial1", "ip": "1.2.3.4"})
+
+	c.Assert(FieldMap{"ip": "ip"}.Validate(), NotNil)
+	c.Assert(DefaultServiceNowFieldMap.Validate(), IsNil)
+	c.Assert(DefaultRESTFieldMap.Validate(), IsNil)
+}
+
+func (s *cmdbSuite) TestRetryBackoff(c *C) {
+	r := RetryConfig{MaxAttempts: 5, InitialBackoffSecs: 1, MaxBackoffSecs: 5}
+	c.Assert(r.backoff(1), Equals, 1*time.Second)
+	c.Assert(r.backoff(2), Equals, 2*time.Second)
+	c.Assert(r.backoff(3), Equals, 4*time.Second)
+	c.Assert(r.backoff(4), Equals, 5*time.Second)
+}
+
+type fakeDriver struct {
+	failures int
+	attempts int
+	err      error
+}
+
+func (d *fakeDriver) Upsert(name string, record map[string]string) error {
+	d.attempts++
+	if d.attempts <= d.failures {
+		if d.err != nil {
+			return d.err
+		}
+		return errored.Errorf("test failure")
+	}
+	return nil
+}
+
+func (s *cmdbSuite) TestSyncRetry(c *C) {
+	d := &fakeDriver{failures: 2}
+	syncer, err := NewAsyncSyncer(d, DefaultRESTFieldMap, RetryConfig{MaxAttempts: 3, InitialBackoffSecs: 1,
+		MaxBackoffSecs: 10})
+	c.Assert(err, IsNil)
+	backoffs := []time.Duration{}
+	syncer.sleep = func(d time.Duration) { backoffs = append(backoffs, d) }
+
+	c.Assert(syncer.sync(testNode), IsNil)
+	c.Assert(d.attempts, Equals, 3)
+	c.Assert(backoffs, DeepEquals, []time.Duration{1 * time.Second, 2 * time.Second})
+
+	d = &fakeDriver{failures: 5}
+	syncer.driver = d
+	c.Assert(syncer.sync(testNode), ErrorMatches, "giving up after 3 attempt.*test failure")
+	c.Assert(d.attempts, Equals, 3)
+
+	// an invalid record is not retried
+	d = &fakeDriver{failures: 5, err: clustererr.New(clustererr.Validation, "invalid key")}
+	syncer.driver = d
+	c.Assert(syncer.sync(testNode), ErrorMatches, "invalid key")
+	c.Assert(d.attempts, Equals, 1)
+}
+
+func (s *cmdbSuite) TestSyncNoRetry(c *C) {
+	// the sync is attempted once when the max attempts are not set
+	c.Assert(RetryConfig{}.policy().MaxAttempts, Equals, 1)
+	c.Assert(RetryConfig{MaxAttempts: -1}.policy().MaxAttempts, Equals, 1)
+
+	d := &fakeDriver{failures: 5}
+	syncer, err := NewAsyncSyncer(d, DefaultRESTFieldMap, RetryConfig{})
+	c.Assert(err, IsNil)
+	syncer.sleep = func(time.Duration) { c.Fatal("sync retried") }
+	c.Assert(syncer.sync(testNode), ErrorMatches, "test failure")
+	c.Assert(d.attempts, Equals, 1)
+}
+
+func (s *cmdbSuite) TestServiceNowUpsert(c *C) {
+	created := map[string]string{}
+	patched := map[string]string{}
+	existing := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		c.Assert(user+":"+pass, Equals, "admin:secret")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/now/table/cmdb_ci_server":
+			c.Assert(r.URL.Query().Get("sysparm_query"), Equals, "name=node1-serial1")
+			if existing {
+				w.Write([]byte(`{"result": [{"sys_id": "abc123"}]}`))
+				return
+			}
+			w.Write([]byte(`{"result": []}`))
+		case r.Method == "POST" && r.URL.Path == "/api/now/table/cmdb_ci_server":
+			c.Assert(json.NewDecoder(r.Body).Decode(&created), IsNil)
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == "PATCH" && r.URL.Path == "/api/now/table/cmdb_ci_server/abc123":
+			c.Assert(json.NewDecoder(r.Body).Decode(&patched), IsNil)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	d := NewServiceNowDriver(ServiceNowConfig{InstanceURL: ts.URL, User: "admin", Password: "secret"})
+	record := DefaultServiceNowFieldMap.Record(testNode)
+	c.Assert(d.Upsert(testNode.Name, record), IsNil)
+	c.Assert(created, DeepEquals, record)
+	c.Assert(patched, HasLen, 0)
+
+	existing = true
+	c.Assert(d.Upsert(testNode.Name, record), IsNil)
+	c.Assert(patched, DeepEquals, record)
+
+	// a key that would add clauses to the query is rejected
+	record["name"] = "node1^ORname!=node1"
+	err := d.Upsert(testNode.Name, record)
+	c.Assert(err, ErrorMatches, ".*contains '\\^', which is not allowed")
+	c.Assert(clustererr.CodeOf(err), Equals, clustererr.Validation)
+}
+
+func (s *cmdbSuite) TestRESTUpsert(c *C) {
+	var (
+		method, path, auth string
+		body               map[string]string
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, auth = r.Method, r.URL.Path, r.Header.Get("Authorization")
+		c.Assert(json.NewDecoder(r.Body).Decode(&body), IsNil)
+		if r.Header.Get("Authorization") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer ts.Close()
+
+	d := NewRESTDriver(RESTConfig{URL: ts.URL + "/nodes/", Headers: map[string]string{"Authorization": "Bearer t"}})
+	c.Assert(d.Upsert(testNode.Name, map[string]string{"name": testNode.Name}), IsNil)
+	c.Assert(method, Equals, "PUT")
+	c.Assert(path, Equals, "/nodes/node1-serial1")
+	c.Assert(auth, Equals, "Bearer t")
+	c.Assert(body, DeepEquals, map[string]string{"name": testNode.Name})
+
+	d = NewRESTDriver(RESTConfig{URL: ts.URL + "/nodes"})
+	c.Assert(d.Upsert(testNode.Name, map[string]string{}), NotNil)
+}
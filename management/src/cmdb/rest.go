@@ -0,0 +1,84 @@
+package cmdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/contiv/errored"
+)
+
+// RESTConfig denotes the configuration for syncing nodes to a generic REST endpoint.
+// A node's record is sent as a json object to <url>/<node name>.
+type RESTConfig struct {
+	URL string `json:"url"`
+	// Method is the http method used to upsert the record, it is PUT by default
+	Method string `json:"method,omitempty"`
+	// Headers are the additional headers sent with requests, like the authorization header
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// DefaultRESTFieldMap is the field mapping used for REST endpoint when none is configured
+var DefaultRESTFieldMap = FieldMap{
+	FieldName:      FieldName,
+	FieldAddr:      FieldAddr,
+	FieldHostGroup: FieldHostGroup,
+	FieldStatus:    FieldStatus,
+	FieldState:     FieldState,
+	FieldSerial:    FieldSerial,
+	FieldLabel:     FieldLabel,
+}
+
+// RESTDriver implements the CMDB driver for a generic REST endpoint
+type RESTDriver struct {
+	config RESTConfig
+	client *http.Client
+}
+
+// NewRESTDriver initializes and returns an instance of REST CMDB driver
+func NewRESTDriver(config RESTConfig) *RESTDriver {
+	if config.Method == "" {
+		config.Method = "PUT"
+	}
+	return &RESTDriver{
+		config: config,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Upsert implements the upsert interface of CMDB driver
+func (d *RESTDriver) Upsert(name string, record map[string]string) error {
+	var reqBody bytes.Buffer
+	if err := json.NewEncoder(&reqBody).Encode(record); err != nil {
+		return err
+	}
+	req, err := http.NewRequest(d.config.Method, strings.TrimSuffix(d.config.URL, "/")+"/"+url.PathEscape(name),
+		&reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range d.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			body = []byte{}
+		}
+		return errored.Errorf("cmdb request for node %q failed. Status code %d unexpected. Response body: %q",
+			name, resp.StatusCode, body)
+	}
+	return nil
+}
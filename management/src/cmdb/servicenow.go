@@ -0,0 +1,131 @@
+package cmdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/contiv/cluster/management/src/clustererr"
+	"github.com/contiv/errored"
+)
+
+// ServiceNowConfig denotes the configuration for syncing nodes to ServiceNow CMDB
+// through it's table api
+type ServiceNowConfig struct {
+	// InstanceURL is the url of the ServiceNow instance, like https://example.service-now.com
+	InstanceURL string `json:"instance_url"`
+	User        string `json:"user"`
+	Password    string `json:"password"`
+	// Table is the CMDB configuration item table that nodes are synced to
+	Table string `json:"table"`
+	// KeyField is the CMDB field that identifies a node's record, it needs to be
+	// mapped to the node's name in the field mapping
+	KeyField string `json:"key_field"`
+}
+
+// DefaultServiceNowFieldMap is the field mapping used for ServiceNow when none is configured
+var DefaultServiceNowFieldMap = FieldMap{
+	"name":               FieldName,
+	"ip_address":         FieldAddr,
+	"serial_number":      FieldSerial,
+	"u_host_group":       FieldHostGroup,
+	"u_lifecycle_status": FieldStatus,
+	"u_monitoring_state": FieldState,
+}
+
+// ServiceNowDriver implements the CMDB driver for ServiceNow
+type ServiceNowDriver struct {
+	config ServiceNowConfig
+	client *http.Client
+}
+
+// NewServiceNowDriver initializes and returns an instance of ServiceNow CMDB driver
+func NewServiceNowDriver(config ServiceNowConfig) *ServiceNowDriver {
+	if config.Table == "" {
+		config.Table = "cmdb_ci_server"
+	}
+	if config.KeyField == "" {
+		config.KeyField = "name"
+	}
+	return &ServiceNowDriver{
+		config: config,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type serviceNowResult struct {
+	SysID string `json:"sys_id"`
+}
+
+func (d *ServiceNowDriver) request(method, rsrc string, req interface{}, resp interface{}) error {
+	var reqBody io.Reader
+	if req != nil {
+		var b bytes.Buffer
+		if err := json.NewEncoder(&b).Encode(req); err != nil {
+			return err
+		}
+		reqBody = &b
+	}
+	httpReq, err := http.NewRequest(method, d.config.InstanceURL+"/api/now/table/"+rsrc, reqBody)
+	if err != nil {
+		return err
+	}
+	httpReq.SetBasicAuth(d.config.User, d.config.Password)
+	httpReq.Header.Set("Accept", "application/json")
+	if req != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	httpResp, err := d.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+
+	body, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return err
+	}
+	if httpResp.StatusCode != http.StatusOK && httpResp.StatusCode != http.StatusCreated {
+		return errored.Errorf("servicenow request %s %q failed. Status code %d unexpected. Response body: %q",
+			method, rsrc, httpResp.StatusCode, body)
+	}
+	if resp == nil {
+		return nil
+	}
+	return json.Unmarshal(body, resp)
+}
+
+// Upsert implements the upsert interface of CMDB driver
+func (d *ServiceNowDriver) Upsert(name string, record map[string]string) error {
+	key, ok := record[d.config.KeyField]
+	if !ok {
+		key = name
+	}
+	// '^' separates the clauses of an encoded query, so a key with it would
+	// match other records than the node's
+	if strings.Contains(key, "^") {
+		return clustererr.New(clustererr.Validation, "servicenow key %q of node %q contains '^', which is not allowed",
+			key, name)
+	}
+	query := url.Values{}
+	query.Set("sysparm_query", d.config.KeyField+"="+key)
+	query.Set("sysparm_fields", "sys_id")
+	query.Set("sysparm_limit", "1")
+	found := struct {
+		Result []serviceNowResult `json:"result"`
+	}{}
+	if err := d.request("GET", d.config.Table+"?"+query.Encode(), nil, &found); err != nil {
+		return err
+	}
+
+	if len(found.Result) == 0 {
+		return d.request("POST", d.config.Table, record, nil)
+	}
+	return d.request("PATCH", d.config.Table+"/"+found.Result[0].SysID, record, nil)
+}
@@ -35,6 +35,40 @@ type Asset struct {
 	State  struct {
 		Name string `json:"NAME"`
 	}
+	// IPMI and Attributes are not part of the asset object in collins, these
+	// are read from the asset details instead, when available.
+	IPMI       *IPMI             `json:"-"`
+	Attributes map[string]string `json:"-"`
+}
+
+// IPMI denotes the IPMI related information of an asset as read from collins.
+type IPMI struct {
+	Address  string `json:"IPMI_ADDRESS"`
+	Username string `json:"IPMI_USERNAME"`
+	Password string `json:"IPMI_PASSWORD"`
+}
+
+// assetDetails denotes the asset details as returned by the collins' assets api
+type assetDetails struct {
+	Asset   Asset                        `json:"ASSET"`
+	IPMI    *IPMI                        `json:"IPMI"`
+	Attribs map[string]map[string]string `json:"ATTRIBS"`
+}
+
+// toAsset returns the asset populated with IPMI info and attributes from
+// the details. The attributes are keyed by their lower-cased names.
+func (d *assetDetails) toAsset() Asset {
+	a := d.Asset
+	a.IPMI = d.IPMI
+	for _, attribs := range d.Attribs {
+		for k, v := range attribs {
+			if a.Attributes == nil {
+				a.Attributes = make(map[string]string)
+			}
+			a.Attributes[strings.ToLower(k)] = v
+		}
+	}
+	return a
 }
 
 // Client denotes state for a collins client
@@ -129,7 +163,10 @@ func (c *Client) GetAsset(tag string) (Asset, error) {
 
 // GetAllAssets queries and returns a all the assets
 func (c *Client) GetAllAssets() (interface{}, error) {
-	reqURL := c.config.URL + "/api/assets"
+	params := &url.Values{}
+	params.Set("details", "true")
+
+	reqURL := c.config.URL + "/api/assets?" + params.Encode()
 	req, err := http.NewRequest("GET", reqURL, nil)
 	if err != nil {
 		return nil, err
@@ -154,9 +191,7 @@ func (c *Client) GetAllAssets() (interface{}, error) {
 	logrus.Debugf("response: %s", body)
 	collinsResp := &struct {
 		Data struct {
-			Assets []struct {
-				Asset Asset `json:"ASSET"`
-			} `json:"Data"`
+			Assets []assetDetails `json:"Data"`
 		} `json:"data"`
 	}{}
 	if err := json.Unmarshal(body, collinsResp); err != nil {
@@ -166,7 +201,7 @@ func (c *Client) GetAllAssets() (interface{}, error) {
 	assets := []Asset{}
 	for _, d := range collinsResp.Data.Assets {
 		logrus.Debugf("collins asset: %+v", d.Asset)
-		assets = append(assets, d.Asset)
+		assets = append(assets, d.toAsset())
 	}
 	return assets, nil
 }
@@ -232,6 +232,39 @@ func (s *collinsSuite) TestGetAllAssets(c *C) {
 	c.Assert(rcvdAssets[0], DeepEquals, asset)
 }
 
+func (s *collinsSuite) TestGetAllAssetsWithDetails(c *C) {
+	srvr, httpC := getHTTPTestClientAndServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/assets" || r.URL.Query().Get("details") != "true" {
+				http.Error(w, "unexpected request", http.StatusInternalServerError)
+				return
+			}
+			w.Write([]byte(`{
+				"data": {
+					"Data": [{
+						"ASSET": {"TAG": "test", "STATUS": "status", "STATE": {"NAME": "state"}},
+						"IPMI": {"IPMI_ADDRESS": "1.2.3.4", "IPMI_USERNAME": "admin", "IPMI_PASSWORD": "secret"},
+						"ATTRIBS": {"0": {"RACK": "r1"}}
+					}]
+				}
+			}`))
+		}))
+	defer srvr.Close()
+	client := &Client{
+		config: DefaultConfig(),
+		client: httpC,
+	}
+
+	rcvdAssets_, err := client.GetAllAssets()
+	c.Assert(err, IsNil)
+	rcvdAssets, ok := rcvdAssets_.([]Asset)
+	c.Assert(ok, Equals, true)
+	c.Assert(len(rcvdAssets), Equals, 1)
+	c.Assert(rcvdAssets[0].Tag, Equals, "test")
+	c.Assert(rcvdAssets[0].IPMI, DeepEquals, &IPMI{Address: "1.2.3.4", Username: "admin", Password: "secret"})
+	c.Assert(rcvdAssets[0].Attributes, DeepEquals, map[string]string{"rack": "r1"})
+}
+
 func (s *collinsSuite) TestGetAllAssetsStatusFailure(c *C) {
 	srvr, httpC := getHTTPTestClientAndServer(failureReturner)
 	defer srvr.Close()
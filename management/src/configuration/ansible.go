@@ -3,6 +3,7 @@ package configuration
 import (
 	"encoding/json"
 	"io"
+	"os"
 	"strings"
 
 	"golang.org/x/net/context"
@@ -28,6 +29,7 @@ type AnsibleSubsysConfig struct {
 type AnsibleSubsys struct {
 	config          *AnsibleSubsysConfig
 	globalExtraVars string
+	resolver        SecretResolver // nil when secret references are not resolved
 }
 
 // AnsibleHost describes host related info relevant for ansible inventory
@@ -91,6 +93,11 @@ func NewAnsibleSubsys(config *AnsibleSubsysConfig) *AnsibleSubsys {
 	}
 }
 
+// SetSecretResolver sets the resolver for the secret references in extra vars and host vars
+func (a *AnsibleSubsys) SetSecretResolver(r SecretResolver) {
+	a.resolver = r
+}
+
 func mergeExtraVars(dst, src string) (string, error) {
 	var (
 		d map[string]interface{}
@@ -118,11 +125,6 @@ func (a *AnsibleSubsys) ansibleRunner(nodes []*AnsibleHost, playbook, extraVars
 	// make error channel buffered, so it doesn't block
 	errCh := make(chan error, 1)
 
-	iNodes := []ansible.InventoryHost{}
-	for _, n := range nodes {
-		iNodes = append(iNodes, ansible.NewInventoryHost(n.tag, n.addr, n.group, n.vars))
-	}
-
 	// Pick extra variables for ansible, if any.
 	// Merge the variables with following precedence (top one taking higher precedence):
 	// - variables specified per action (i.e. configure, cleanup, upgrade)
@@ -145,12 +147,43 @@ func (a *AnsibleSubsys) ansibleRunner(nodes []*AnsibleHost, playbook, extraVars
 		return nil, nil, errCh
 	}
 
+	// resolve the secret references, if any
+	hostVars := map[*AnsibleHost]map[string]string{}
+	for _, n := range nodes {
+		hostVars[n] = n.vars
+	}
+	varsFile := ""
+	if a.resolver != nil {
+		var resolved bool
+		if vars, resolved, err = a.resolveSecrets(vars, hostVars); err != nil {
+			errCh <- err
+			return nil, nil, errCh
+		}
+		if resolved {
+			// pass the extra vars in a file, so that the secrets don't show up in
+			// the process list or logs
+			if varsFile, err = writeExtraVarsFile(vars); err != nil {
+				errCh <- err
+				return nil, nil, errCh
+			}
+			vars = "@" + varsFile
+		}
+	}
+
+	iNodes := []ansible.InventoryHost{}
+	for _, n := range nodes {
+		iNodes = append(iNodes, ansible.NewInventoryHost(n.tag, n.addr, n.group, hostVars[n]))
+	}
+
 	ctxt, cancelFunc := context.WithCancel(context.Background())
 	runner := ansible.NewRunner(ansible.NewInventory(iNodes), playbook, a.config.User,
 		a.config.PrivKeyFile, vars, ctxt)
 	r, w := io.Pipe()
 	go func(outStream io.Writer, errCh chan error) {
 		defer r.Close()
+		if varsFile != "" {
+			defer os.Remove(varsFile)
+		}
 		if err := runner.Run(outStream, outStream); err != nil {
 			errCh <- err
 			return
@@ -4,6 +4,7 @@ package configuration
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	. "gopkg.in/check.v1"
@@ -69,3 +70,63 @@ func (s *ansibleSuite) TestMergeExtraVarsInvalidJSON(c *C) {
 	c.Assert(err, ErrorMatches, "failed to unmarshal src extra vars.*",
 		Commentf("output string: %s", out))
 }
+
+type fakeResolver struct {
+	refs []string
+}
+
+func (r *fakeResolver) IsRef(val string) bool {
+	return strings.HasPrefix(val, "vault:")
+}
+
+func (r *fakeResolver) Resolve(refs []string) (map[string]string, error) {
+	r.refs = refs
+	vals := map[string]string{}
+	for _, ref := range refs {
+		vals[ref] = "resolved-" + strings.TrimPrefix(ref, "vault:")
+	}
+	return vals, nil
+}
+
+func (s *ansibleSuite) TestResolveSecrets(c *C) {
+	resolver := &fakeResolver{}
+	a := NewAnsibleSubsys(&AnsibleSubsysConfig{})
+	a.SetSecretResolver(resolver)
+
+	host := NewAnsibleHost("node1", "1.2.3.4", "service-master", map[string]string{
+		"registry_password": "vault:secret/registry#password",
+		"node_name":         "node1",
+	})
+	hostVars := map[*AnsibleHost]map[string]string{host: host.vars}
+	vars, resolved, err := a.resolveSecrets(`{"env": {}, "registry": {"password": "vault:secret/registry#password",
+		"tokens": ["vault:secret/tokens#t1"]}}`, hostVars)
+	c.Assert(err, IsNil)
+	c.Assert(resolved, Equals, true)
+	// a reference is resolved only once
+	c.Assert(resolver.refs, HasLen, 2)
+
+	var varsMap map[string]interface{}
+	c.Assert(json.Unmarshal([]byte(vars), &varsMap), IsNil)
+	c.Assert(varsMap, DeepEquals, map[string]interface{}{
+		"env": map[string]interface{}{},
+		"registry": map[string]interface{}{
+			"password": "resolved-secret/registry#password",
+			"tokens":   []interface{}{"resolved-secret/tokens#t1"},
+		},
+	})
+	c.Assert(hostVars[host], DeepEquals, map[string]string{
+		"registry_password": "resolved-secret/registry#password",
+		"node_name":         "node1",
+	})
+	// the host retains the reference
+	c.Assert(host.vars["registry_password"], Equals, "vault:secret/registry#password")
+}
+
+func (s *ansibleSuite) TestResolveSecretsNoRefs(c *C) {
+	a := NewAnsibleSubsys(&AnsibleSubsysConfig{})
+	a.SetSecretResolver(&fakeResolver{})
+	vars, resolved, err := a.resolveSecrets(`{"env": {}}`, map[*AnsibleHost]map[string]string{})
+	c.Assert(err, IsNil)
+	c.Assert(resolved, Equals, false)
+	c.Assert(vars, Equals, `{"env": {}}`)
+}
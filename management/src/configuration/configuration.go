@@ -26,6 +26,15 @@ type Subsys interface {
 	GetGlobals() string
 }
 
+// SecretResolver resolves the secret references found in the extra vars and host
+// vars, at the time a configuration action is triggered
+type SecretResolver interface {
+	// IsRef returns true if the value is a secret reference
+	IsRef(val string) bool
+	// Resolve returns the secret values of the references
+	Resolve(refs []string) (map[string]string, error)
+}
+
 // SubsysHost denotes a host in configuration subsystem
 type SubsysHost interface {
 	// GetTag returns the name/tag associated with the host in configuration sub-system
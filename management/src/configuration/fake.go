@@ -0,0 +1,112 @@
+package configuration
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/contiv/cluster/management/src/clustererr"
+	"github.com/contiv/errored"
+	"golang.org/x/net/context"
+)
+
+// FakeSubsysConfig describes the configuration for the fake configuration
+// management subsystem, that simulates the configuration actions without
+// running them on the hosts
+type FakeSubsysConfig struct {
+	// StepMsecs is the time it takes to simulate an action on a host
+	StepMsecs int `json:"step_msecs"`
+	// FailHosts are the tags of the hosts, the actions on which fail
+	FailHosts []string `json:"fail_hosts,omitempty"`
+}
+
+// FakeSubsys implements a configuration subsystem that simulates the configure,
+// cleanup and upgrade actions. It is useful to exercise the cluster management
+// workflows without real hosts or ansible.
+type FakeSubsys struct {
+	config          FakeSubsysConfig
+	globalExtraVars string
+}
+
+// NewFakeSubsys instantiates and returns a FakeSubsys
+func NewFakeSubsys(config FakeSubsysConfig) *FakeSubsys {
+	return &FakeSubsys{
+		config:          config,
+		globalExtraVars: DefaultValidJSON,
+	}
+}
+
+func (f *FakeSubsys) fails(tag string) bool {
+	for _, h := range f.config.FailHosts {
+		if h == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// fakeRunner simulates the action on the hosts one at a time, writing ansible
+// like output for each host
+func (f *FakeSubsys) fakeRunner(nodes []*AnsibleHost, action, extraVars string) (io.Reader, context.CancelFunc, chan error) {
+	// make error channel buffered, so it doesn't block
+	errCh := make(chan error, 1)
+
+	vars := DefaultValidJSON
+	for _, v := range []string{f.globalExtraVars, extraVars} {
+		var err error
+		if vars, err = mergeExtraVars(vars, v); err != nil {
+			errCh <- err
+			return nil, nil, errCh
+		}
+	}
+
+	ctxt, cancelFunc := context.WithCancel(context.Background())
+	r, w := io.Pipe()
+	go func() {
+		defer w.Close()
+		fmt.Fprintf(w, "PLAY [simulated %s] with extra vars: %s\n", action, vars)
+		for _, n := range nodes {
+			select {
+			case <-ctxt.Done():
+				errCh <- errored.Errorf("simulated %s was cancelled", action)
+				return
+			case <-time.After(time.Duration(f.config.StepMsecs) * time.Millisecond):
+			}
+			if f.fails(n.tag) {
+				fmt.Fprintf(w, "fatal: [%s] (%s): simulated failure\n", n.tag, n.group)
+				errCh <- clustererr.NewForNode(clustererr.Provisioner, n.tag, "simulated %s failed on host %q", action, n.tag)
+				return
+			}
+			fmt.Fprintf(w, "ok: [%s] (%s)\n", n.tag, n.group)
+		}
+		fmt.Fprintf(w, "PLAY RECAP: %d host(s) ok\n", len(nodes))
+		errCh <- nil
+	}()
+	return r, cancelFunc, errCh
+}
+
+// Configure simulates the configuration on specified nodes
+func (f *FakeSubsys) Configure(nodes SubsysHosts, extraVars string) (io.Reader, context.CancelFunc, chan error) {
+	return f.fakeRunner(nodes.([]*AnsibleHost), "configure", extraVars)
+}
+
+// Cleanup simulates the cleanup on specified nodes
+func (f *FakeSubsys) Cleanup(nodes SubsysHosts, extraVars string) (io.Reader, context.CancelFunc, chan error) {
+	return f.fakeRunner(nodes.([]*AnsibleHost), "cleanup", extraVars)
+}
+
+// Upgrade simulates the upgrade on specified nodes
+func (f *FakeSubsys) Upgrade(nodes SubsysHosts, extraVars string) (io.Reader, context.CancelFunc, chan error) {
+	return f.fakeRunner(nodes.([]*AnsibleHost), "upgrade", extraVars)
+}
+
+// SetGlobals sets the extra vars at the fake subsys level
+func (f *FakeSubsys) SetGlobals(extraVars string) error {
+	f.globalExtraVars = extraVars
+	return nil
+}
+
+// GetGlobals return the value of extra vars at the fake subsys level
+func (f *FakeSubsys) GetGlobals() string {
+	return f.globalExtraVars
+}
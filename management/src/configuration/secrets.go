@@ -0,0 +1,97 @@
+package configuration
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	"github.com/contiv/errored"
+)
+
+// walkStrings calls the function for each string value in the json decoded value and
+// replaces the value with the one returned by the function
+func walkStrings(v interface{}, fn func(string) string) interface{} {
+	switch val := v.(type) {
+	case string:
+		return fn(val)
+	case map[string]interface{}:
+		for k, e := range val {
+			val[k] = walkStrings(e, fn)
+		}
+	case []interface{}:
+		for i, e := range val {
+			val[i] = walkStrings(e, fn)
+		}
+	}
+	return v
+}
+
+// resolveSecrets resolves the secret references in the extra vars and host vars. The
+// host vars are replaced with copies that have the secrets, so that the secrets are
+// not retained with the hosts. It also returns true if any reference was resolved.
+func (a *AnsibleSubsys) resolveSecrets(extraVars string, hostVars map[*AnsibleHost]map[string]string) (string, bool, error) {
+	var vars interface{}
+	if err := json.Unmarshal([]byte(extraVars), &vars); err != nil {
+		return "", false, errored.Errorf("failed to unmarshal extra vars. Error: %v", err)
+	}
+
+	// collect the references
+	refs := []string{}
+	seen := map[string]struct{}{}
+	collect := func(val string) string {
+		if _, ok := seen[val]; !ok && a.resolver.IsRef(val) {
+			seen[val] = struct{}{}
+			refs = append(refs, val)
+		}
+		return val
+	}
+	walkStrings(vars, collect)
+	for _, hv := range hostVars {
+		for _, val := range hv {
+			collect(val)
+		}
+	}
+	if len(refs) == 0 {
+		return extraVars, false, nil
+	}
+
+	secrets, err := a.resolver.Resolve(refs)
+	if err != nil {
+		return "", false, errored.Errorf("failed to resolve secret references. Error: %v", err)
+	}
+	resolve := func(val string) string {
+		if secret, ok := secrets[val]; ok {
+			return secret
+		}
+		return val
+	}
+
+	vars = walkStrings(vars, resolve)
+	o, err := json.Marshal(vars)
+	if err != nil {
+		return "", false, errored.Errorf("failed to marshal resolved extra vars. Error: %v", err)
+	}
+	for h, hv := range hostVars {
+		resolved := map[string]string{}
+		for k, val := range hv {
+			resolved[k] = resolve(val)
+		}
+		hostVars[h] = resolved
+	}
+	return string(o), true, nil
+}
+
+// writeExtraVarsFile writes the extra vars to a file readable only by the owner and
+// returns it's name. The caller shall delete the file after use
+func writeExtraVarsFile(vars string) (string, error) {
+	f, err := ioutil.TempFile("", "extra-vars")
+	if err != nil {
+		return "", errored.Errorf("failed to create extra vars file. Error: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(vars); err != nil {
+		os.Remove(f.Name())
+		return "", errored.Errorf("failed to write extra vars file. Error: %v", err)
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,71 @@
+package dns
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/contiv/errored"
+)
+
+// BindConfig denotes the configuration for bind based DNS subsystem, where
+// records are updated dynamically using TSIG authenticated nsupdate.
+type BindConfig struct {
+	Server string `json:"server"`
+	Zone   string `json:"zone"`
+	// KeyName, KeySecret and KeyAlgorithm identify the TSIG key
+	KeyName      string `json:"key_name"`
+	KeySecret    string `json:"key_secret"`
+	KeyAlgorithm string `json:"key_algorithm"`
+}
+
+// BindSubsys implements the DNS subsystem for bind using nsupdate
+type BindSubsys struct {
+	config BindConfig
+}
+
+// NewBindSubsys initializes and returns an instance of bind based DNS subsystem
+func NewBindSubsys(config BindConfig) *BindSubsys {
+	if config.KeyAlgorithm == "" {
+		config.KeyAlgorithm = "hmac-sha256"
+	}
+	return &BindSubsys{
+		config: config,
+	}
+}
+
+// nsupdateScript returns the nsupdate commands to run the update. The TSIG key is
+// passed as part of the commands so it doesn't show up in process list.
+func (s *BindSubsys) nsupdateScript(update string) string {
+	cmds := []string{fmt.Sprintf("server %s", s.config.Server)}
+	if s.config.KeyName != "" {
+		cmds = append(cmds, fmt.Sprintf("key %s:%s %s", s.config.KeyAlgorithm, s.config.KeyName,
+			s.config.KeySecret))
+	}
+	cmds = append(cmds, fmt.Sprintf("zone %s", s.config.Zone), update, "send", "")
+	return strings.Join(cmds, "\n")
+}
+
+func (s *BindSubsys) nsupdate(update string, r *Record) error {
+	c := exec.Command("nsupdate")
+	c.Stdin = strings.NewReader(s.nsupdateScript(update))
+	output, err := c.CombinedOutput()
+	if err != nil {
+		return errored.Errorf("nsupdate of record %s failed. Output: %s, Error: %v", r, output, err)
+	}
+	logrus.Debugf("nsupdate of record %s. Output: %s", r, output)
+	return nil
+}
+
+// AddRecord implements the add record interface of DNS subsystem
+func (s *BindSubsys) AddRecord(r *Record) error {
+	// delete any existing record for the name first, so that update replaces it
+	return s.nsupdate(fmt.Sprintf("update delete %s. %s\nupdate add %s. %d %s %s", r.Name, r.Type(),
+		r.Name, r.TTL, r.Type(), r.Addr), r)
+}
+
+// RemoveRecord implements the remove record interface of DNS subsystem
+func (s *BindSubsys) RemoveRecord(r *Record) error {
+	return s.nsupdate(fmt.Sprintf("update delete %s. %s %s", r.Name, r.Type(), r.Addr), r)
+}
@@ -0,0 +1,100 @@
+package dns
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/contiv/errored"
+)
+
+// CoreDNSConfig denotes the configuration for CoreDNS based DNS subsystem, where
+// records are served by CoreDNS' etcd plugin
+type CoreDNSConfig struct {
+	// EtcdURL is the client url of etcd v3 json api
+	EtcdURL string `json:"etcd_url"`
+	// PathPrefix is the etcd path prefix configured for the CoreDNS' etcd plugin
+	PathPrefix string `json:"path_prefix"`
+}
+
+// CoreDNSSubsys implements the DNS subsystem for CoreDNS by writing records in etcd
+type CoreDNSSubsys struct {
+	config CoreDNSConfig
+	client *http.Client
+}
+
+// NewCoreDNSSubsys initializes and returns an instance of CoreDNS based DNS subsystem
+func NewCoreDNSSubsys(config CoreDNSConfig) *CoreDNSSubsys {
+	if config.PathPrefix == "" {
+		config.PathPrefix = "/skydns"
+	}
+	return &CoreDNSSubsys{
+		config: config,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// recordKey returns the etcd key of the record. The CoreDNS' etcd plugin expects
+// the domain name labels in reverse order i.e. node1.example.com is stored at
+// <prefix>/com/example/node1
+func (s *CoreDNSSubsys) recordKey(r *Record) string {
+	labels := strings.Split(strings.ToLower(r.Name), ".")
+	for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+		labels[i], labels[j] = labels[j], labels[i]
+	}
+	return strings.TrimSuffix(s.config.PathPrefix, "/") + "/" + strings.Join(labels, "/")
+}
+
+func (s *CoreDNSSubsys) post(rsrc string, req interface{}, r *Record) error {
+	var reqBody bytes.Buffer
+	if err := json.NewEncoder(&reqBody).Encode(req); err != nil {
+		return err
+	}
+
+	resp, err := s.client.Post(s.config.EtcdURL+rsrc, "application/json", &reqBody)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			body = []byte{}
+		}
+		return errored.Errorf("etcd request %q for record %s failed. Status code %d unexpected. Response body: %q",
+			rsrc, r, resp.StatusCode, body)
+	}
+	return nil
+}
+
+// AddRecord implements the add record interface of DNS subsystem
+func (s *CoreDNSSubsys) AddRecord(r *Record) error {
+	val, err := json.Marshal(struct {
+		Host string `json:"host"`
+		TTL  int    `json:"ttl"`
+	}{Host: r.Addr, TTL: r.TTL})
+	if err != nil {
+		return err
+	}
+	return s.post("/v3/kv/put", struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}{
+		Key:   base64.StdEncoding.EncodeToString([]byte(s.recordKey(r))),
+		Value: base64.StdEncoding.EncodeToString(val),
+	}, r)
+}
+
+// RemoveRecord implements the remove record interface of DNS subsystem
+func (s *CoreDNSSubsys) RemoveRecord(r *Record) error {
+	return s.post("/v3/kv/deleterange", struct {
+		Key string `json:"key"`
+	}{
+		Key: base64.StdEncoding.EncodeToString([]byte(s.recordKey(r))),
+	}, r)
+}
@@ -0,0 +1,88 @@
+package dns
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"text/template"
+
+	"github.com/contiv/errored"
+)
+
+// Record denotes a DNS address record of a node
+type Record struct {
+	Name string
+	Addr string
+	TTL  int
+}
+
+// Type returns the DNS record type viz. A or AAAA based on the record's address
+func (r *Record) Type() string {
+	if ip := net.ParseIP(r.Addr); ip != nil && ip.To4() == nil {
+		return "AAAA"
+	}
+	return "A"
+}
+
+// String returns the description of the record
+func (r *Record) String() string {
+	return r.Name + " " + r.Type() + " " + r.Addr
+}
+
+// Subsys provides the following services to the cluster manager:
+// - Interface to create and remove DNS records of nodes in a DNS backend.
+type Subsys interface {
+	// AddRecord creates or updates the record
+	AddRecord(r *Record) error
+	// RemoveRecord removes the record
+	RemoveRecord(r *Record) error
+}
+
+// RecordHost denotes the node info available to record name templates
+type RecordHost struct {
+	Name      string
+	Addr      string
+	HostGroup string
+}
+
+// RecordNamer forms the record names of nodes from templates specified per host-group
+type RecordNamer struct {
+	defaultTmpl *template.Template
+	groupTmpls  map[string]*template.Template
+}
+
+// NewRecordNamer parses the templates and returns a RecordNamer. The defaultTmpl
+// is used for host-groups that don't have a template in groupTmpls.
+func NewRecordNamer(defaultTmpl string, groupTmpls map[string]string) (*RecordNamer, error) {
+	var err error
+	n := &RecordNamer{
+		groupTmpls: make(map[string]*template.Template),
+	}
+	if n.defaultTmpl, err = template.New("default").Parse(defaultTmpl); err != nil {
+		return nil, errored.Errorf("failed to parse default record name template. Error: %v", err)
+	}
+	for group, tmpl := range groupTmpls {
+		if n.groupTmpls[group], err = template.New(group).Parse(tmpl); err != nil {
+			return nil, errored.Errorf("failed to parse record name template for host-group %q. Error: %v",
+				group, err)
+		}
+	}
+	return n, nil
+}
+
+// Name returns the record name of the specified host
+func (n *RecordNamer) Name(host *RecordHost) (string, error) {
+	tmpl, ok := n.groupTmpls[host.HostGroup]
+	if !ok {
+		tmpl = n.defaultTmpl
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, host); err != nil {
+		return "", errored.Errorf("failed to form record name for host %q. Error: %v", host.Name, err)
+	}
+	name := strings.TrimSuffix(strings.TrimSpace(out.String()), ".")
+	if name == "" {
+		return "", errored.Errorf("empty record name formed for host %q", host.Name)
+	}
+	return name, nil
+}
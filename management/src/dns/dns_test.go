@@ -0,0 +1,120 @@
+// +build unittest
+
+package dns
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type dnsSuite struct {
+}
+
+var _ = Suite(&dnsSuite{})
+
+func (s *dnsSuite) TestRecordType(c *C) {
+	c.Assert((&Record{Addr: "1.2.3.4"}).Type(), Equals, "A")
+	c.Assert((&Record{Addr: "fe80::1"}).Type(), Equals, "AAAA")
+}
+
+func (s *dnsSuite) TestRecordNamer(c *C) {
+	namer, err := NewRecordNamer("{{.Name}}.cluster.local.",
+		map[string]string{"service-master": "{{.Name}}.masters.cluster.local"})
+	c.Assert(err, IsNil)
+
+	name, err := namer.Name(&RecordHost{Name: "node1", HostGroup: "service-worker"})
+	c.Assert(err, IsNil)
+	c.Assert(name, Equals, "node1.cluster.local")
+
+	name, err = namer.Name(&RecordHost{Name: "node1", HostGroup: "service-master"})
+	c.Assert(err, IsNil)
+	c.Assert(name, Equals, "node1.masters.cluster.local")
+}
+
+func (s *dnsSuite) TestRecordNamerErrors(c *C) {
+	_, err := NewRecordNamer("{{.Name", nil)
+	c.Assert(err, NotNil)
+
+	_, err = NewRecordNamer("{{.Name}}", map[string]string{"service-master": "{{.Name"})
+	c.Assert(err, NotNil)
+
+	namer, err := NewRecordNamer("{{.Label}}", nil)
+	c.Assert(err, IsNil)
+	_, err = namer.Name(&RecordHost{Name: "node1"})
+	c.Assert(err, NotNil)
+
+	namer, err = NewRecordNamer("", nil)
+	c.Assert(err, IsNil)
+	_, err = namer.Name(&RecordHost{Name: "node1"})
+	c.Assert(err, NotNil)
+}
+
+func (s *dnsSuite) TestRoute53ChangeBatch(c *C) {
+	batch, err := changeBatch("UPSERT", &Record{Name: "node1.cluster.local", Addr: "1.2.3.4", TTL: 60})
+	c.Assert(err, IsNil)
+	c.Assert(batch, Equals, `{"Changes":[{"Action":"UPSERT","ResourceRecordSet":{"Name":"node1.cluster.local",`+
+		`"Type":"A","TTL":60,"ResourceRecords":[{"Value":"1.2.3.4"}]}}]}`)
+}
+
+func (s *dnsSuite) TestBindNsupdateScript(c *C) {
+	subsys := NewBindSubsys(BindConfig{
+		Server:    "10.0.0.1",
+		Zone:      "cluster.local",
+		KeyName:   "clusterm",
+		KeySecret: "c2VjcmV0",
+	})
+	script := subsys.nsupdateScript("update delete node1.cluster.local. A")
+	c.Assert(script, Equals, "server 10.0.0.1\nkey hmac-sha256:clusterm c2VjcmV0\nzone cluster.local\n"+
+		"update delete node1.cluster.local. A\nsend\n")
+}
+
+func (s *dnsSuite) TestCoreDNSRecordKey(c *C) {
+	subsys := NewCoreDNSSubsys(CoreDNSConfig{})
+	c.Assert(subsys.recordKey(&Record{Name: "Node1.Cluster.local"}), Equals, "/skydns/local/cluster/node1")
+}
+
+func (s *dnsSuite) TestCoreDNSAddRemoveRecord(c *C) {
+	reqs := map[string]map[string]string{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := map[string]string{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		reqs[r.URL.Path] = req
+	}))
+	defer ts.Close()
+
+	subsys := NewCoreDNSSubsys(CoreDNSConfig{EtcdURL: ts.URL})
+	r := &Record{Name: "node1.cluster.local", Addr: "1.2.3.4", TTL: 60}
+	c.Assert(subsys.AddRecord(r), IsNil)
+	c.Assert(subsys.RemoveRecord(r), IsNil)
+
+	key := base64.StdEncoding.EncodeToString([]byte("/skydns/local/cluster/node1"))
+	c.Assert(reqs["/v3/kv/put"]["key"], Equals, key)
+	val, err := base64.StdEncoding.DecodeString(reqs["/v3/kv/put"]["value"])
+	c.Assert(err, IsNil)
+	c.Assert(string(val), Equals, `{"host":"1.2.3.4","ttl":60}`)
+	c.Assert(reqs["/v3/kv/deleterange"]["key"], Equals, key)
+}
+
+func (s *dnsSuite) TestCoreDNSRequestFailure(c *C) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	subsys := NewCoreDNSSubsys(CoreDNSConfig{EtcdURL: ts.URL})
+	err := subsys.AddRecord(&Record{Name: "node1.cluster.local", Addr: "1.2.3.4"})
+	c.Assert(err, NotNil)
+	c.Assert(strings.Contains(err.Error(), "500"), Equals, true)
+}
@@ -0,0 +1,83 @@
+package dns
+
+import (
+	"encoding/json"
+	"os/exec"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/contiv/errored"
+)
+
+// Route53Config denotes the configuration for AWS route53 based DNS subsystem.
+// The AWS credentials are picked by the aws cli from it's environment or instance profile.
+type Route53Config struct {
+	HostedZoneID string `json:"hosted_zone_id"`
+}
+
+// Route53Subsys implements the DNS subsystem for AWS route53 using the aws cli
+type Route53Subsys struct {
+	config Route53Config
+}
+
+// NewRoute53Subsys initializes and returns an instance of route53 based DNS subsystem
+func NewRoute53Subsys(config Route53Config) *Route53Subsys {
+	return &Route53Subsys{
+		config: config,
+	}
+}
+
+type route53ChangeBatch struct {
+	Changes []route53Change `json:"Changes"`
+}
+
+type route53Change struct {
+	Action            string `json:"Action"`
+	ResourceRecordSet struct {
+		Name            string `json:"Name"`
+		Type            string `json:"Type"`
+		TTL             int    `json:"TTL"`
+		ResourceRecords []struct {
+			Value string `json:"Value"`
+		} `json:"ResourceRecords"`
+	} `json:"ResourceRecordSet"`
+}
+
+// changeBatch returns the json encoded route53 change batch for the record
+func changeBatch(action string, r *Record) (string, error) {
+	c := route53Change{Action: action}
+	c.ResourceRecordSet.Name = r.Name
+	c.ResourceRecordSet.Type = r.Type()
+	c.ResourceRecordSet.TTL = r.TTL
+	c.ResourceRecordSet.ResourceRecords = []struct {
+		Value string `json:"Value"`
+	}{{Value: r.Addr}}
+	out, err := json.Marshal(route53ChangeBatch{Changes: []route53Change{c}})
+	if err != nil {
+		return "", errored.Errorf("failed to marshal route53 change batch. Error: %v", err)
+	}
+	return string(out), nil
+}
+
+func (s *Route53Subsys) changeRecord(action string, r *Record) error {
+	batch, err := changeBatch(action, r)
+	if err != nil {
+		return err
+	}
+	output, err := exec.Command("aws", "route53", "change-resource-record-sets",
+		"--hosted-zone-id", s.config.HostedZoneID, "--change-batch", batch).CombinedOutput()
+	if err != nil {
+		return errored.Errorf("route53 %s of record %s failed. Output: %s, Error: %v", action, r, output, err)
+	}
+	logrus.Debugf("route53 %s of record %s. Output: %s", action, r, output)
+	return nil
+}
+
+// AddRecord implements the add record interface of DNS subsystem
+func (s *Route53Subsys) AddRecord(r *Record) error {
+	return s.changeRecord("UPSERT", r)
+}
+
+// RemoveRecord implements the remove record interface of DNS subsystem
+func (s *Route53Subsys) RemoveRecord(r *Record) error {
+	return s.changeRecord("DELETE", r)
+}
@@ -0,0 +1,196 @@
+// +build unittest
+
+package gitops
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type gitopsSuite struct {
+}
+
+var _ = Suite(&gitopsSuite{})
+
+func (s *gitopsSuite) TestParseManifest(c *C) {
+	m, err := ParseManifest([]byte(`{"extra_vars": {"env": {}}, "nodes": [
+		{"name": "node1", "host_group": "service-master"},
+		{"name": "node2", "host_group": "service-worker"}]}`))
+	c.Assert(err, IsNil)
+	c.Assert(m.Nodes, DeepEquals, []NodeSpec{
+		{Name: "node1", HostGroup: "service-master"},
+		{Name: "node2", HostGroup: "service-worker"},
+	})
+	c.Assert(m.ExtraVars, DeepEquals, map[string]interface{}{"env": map[string]interface{}{}})
+
+	for _, t := range []struct {
+		manifest string
+		err      string
+	}{
+		{`{"nodes": [`, ".*failed to parse manifest.*"},
+		{`{"nodes": [{"host_group": "service-master"}]}`, ".*doesn't have a name.*"},
+		{`{"nodes": [{"name": "node1"}]}`, ".*node \"node1\" in manifest doesn't have a host-group.*"},
+		{`{"nodes": [{"name": "node1", "host_group": "service-master"},
+			{"name": "node1", "host_group": "service-worker"}]}`, ".*listed more than once.*"},
+	} {
+		_, err := ParseManifest([]byte(t.manifest))
+		c.Assert(err, ErrorMatches, t.err, Commentf("manifest: %s", t.manifest))
+	}
+}
+
+func (s *gitopsSuite) TestComputePlan(c *C) {
+	m := &Manifest{
+		Nodes: []NodeSpec{
+			{Name: "node4", HostGroup: "service-worker"},
+			{Name: "node3", HostGroup: "service-worker"},
+			{Name: "node2", HostGroup: "service-master"},
+			{Name: "node1", HostGroup: "service-master"},
+			{Name: "node5", HostGroup: "service-master"},
+			{Name: "node9", HostGroup: "service-worker"},
+		},
+	}
+	current := map[string]NodeState{
+		"node1": {Commissioned: true, HostGroup: "service-master"},
+		"node2": {Commissioned: false},
+		"node3": {Commissioned: false},
+		"node4": {Commissioned: false},
+		"node5": {Commissioned: true, HostGroup: "service-worker"},
+		"node6": {Commissioned: true, HostGroup: "service-worker"},
+		"node7": {Commissioned: false},
+	}
+	p := ComputePlan(m, current)
+	c.Assert(p.Steps, DeepEquals, []Step{
+		{Action: Commission, Nodes: []string{"node2"}, HostGroup: "service-master"},
+		{Action: Commission, Nodes: []string{"node3", "node4"}, HostGroup: "service-worker"},
+		{Action: Update, Nodes: []string{"node5"}, HostGroup: "service-master"},
+		{Action: Decommission, Nodes: []string{"node6"}},
+	})
+	c.Assert(p.Skipped, DeepEquals, []string{"node9"})
+
+	// no steps when in sync
+	current = map[string]NodeState{
+		"node1": {Commissioned: true, HostGroup: "service-master"},
+		"node7": {Commissioned: false},
+	}
+	p = ComputePlan(&Manifest{Nodes: []NodeSpec{{Name: "node1", HostGroup: "service-master"}}}, current)
+	c.Assert(p.Steps, HasLen, 0)
+	c.Assert(p.Skipped, HasLen, 0)
+}
+
+func (s *gitopsSuite) TestCheckDecommission(c *C) {
+	current := map[string]NodeState{
+		"node1": {Commissioned: true, HostGroup: "service-master"},
+		"node2": {Commissioned: true, HostGroup: "service-worker"},
+		"node3": {Commissioned: true, HostGroup: "service-worker"},
+		"node4": {Commissioned: true, HostGroup: "service-worker"},
+		"node5": {Commissioned: false},
+	}
+	master := NodeSpec{Name: "node1", HostGroup: "service-master"}
+	workers := []NodeSpec{
+		{Name: "node2", HostGroup: "service-worker"},
+		{Name: "node3", HostGroup: "service-worker"},
+		{Name: "node4", HostGroup: "service-worker"},
+	}
+
+	// an empty manifest shall decommission all the nodes
+	p := ComputePlan(&Manifest{Nodes: []NodeSpec{}}, current)
+	c.Assert(CheckDecommission(p, current, 1), ErrorMatches, "refusing to decommission all the 4 commissioned node\\(s\\)")
+
+	p = ComputePlan(&Manifest{Nodes: []NodeSpec{master, workers[0]}}, current)
+	c.Assert(CheckDecommission(p, current, 0.5), IsNil)
+	p = ComputePlan(&Manifest{Nodes: []NodeSpec{master}}, current)
+	c.Assert(CheckDecommission(p, current, 0.5), ErrorMatches,
+		"refusing to decommission 3 of the 4 commissioned node\\(s\\).*")
+	c.Assert(CheckDecommission(p, current, 0.75), IsNil)
+
+	// the plans that don't decommission are allowed
+	p = ComputePlan(&Manifest{Nodes: append([]NodeSpec{master}, workers...)}, current)
+	c.Assert(CheckDecommission(p, current, 0.5), IsNil)
+}
+
+// git runs the git command in the directory
+func git(c *C, dir string, args ...string) string {
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"},
+		args...)...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	c.Assert(err, IsNil, Commentf("git %v: %s", args, out))
+	return strings.TrimSpace(string(out))
+}
+
+// commitManifest commits the manifest in the repository and returns the commit hash
+func commitManifest(c *C, repo, manifest string) string {
+	c.Assert(ioutil.WriteFile(filepath.Join(repo, "manifests.json"), []byte(manifest), 0600), IsNil)
+	git(c, repo, "add", "manifests.json")
+	git(c, repo, "commit", "-q", "-m", "update manifest")
+	return git(c, repo, "rev-parse", "HEAD")
+}
+
+func (s *gitopsSuite) TestWatcherPoll(c *C) {
+	if _, err := exec.LookPath("git"); err != nil {
+		c.Skip("git is not installed")
+	}
+	dir, err := ioutil.TempDir("", "gitops")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+	repo := filepath.Join(dir, "repo")
+	c.Assert(os.Mkdir(repo, 0700), IsNil)
+	git(c, repo, "init", "-q")
+	git(c, repo, "checkout", "-q", "-b", "prod")
+
+	w := NewWatcher(Config{
+		Repo:         repo,
+		Branch:       "prod",
+		ManifestPath: "manifests.json",
+		WorkDir:      filepath.Join(dir, "work"),
+	})
+
+	commit1 := commitManifest(c, repo, `{"nodes": [{"name": "node1", "host_group": "service-master"}]}`)
+	commit, m, err := w.Poll()
+	c.Assert(err, IsNil)
+	c.Assert(commit, Equals, commit1)
+	c.Assert(m.Nodes, DeepEquals, []NodeSpec{{Name: "node1", HostGroup: "service-master"}})
+
+	// no new commit
+	commit, m, err = w.Poll()
+	c.Assert(err, IsNil)
+	c.Assert(commit, Equals, "")
+	c.Assert(m, IsNil)
+
+	// only the head manifest is returned on multiple new commits
+	commitManifest(c, repo, `{"nodes": []}`)
+	commit3 := commitManifest(c, repo, `{"nodes": [{"name": "node2", "host_group": "service-worker"}]}`)
+	commit, m, err = w.Poll()
+	c.Assert(err, IsNil)
+	c.Assert(commit, Equals, commit3)
+	c.Assert(m.Nodes, DeepEquals, []NodeSpec{{Name: "node2", HostGroup: "service-worker"}})
+
+	// an invalid manifest is reported once
+	commit4 := commitManifest(c, repo, `{"nodes": [{"name": "node2"}]}`)
+	commit, _, err = w.Poll()
+	c.Assert(err, NotNil)
+	c.Assert(commit, Equals, commit4)
+	commit, _, err = w.Poll()
+	c.Assert(err, IsNil)
+	c.Assert(commit, Equals, "")
+
+	// the last applied commit is not polled again after a restart
+	w.saveLastCommit(commit4)
+	w = NewWatcher(w.Config())
+	commit, _, err = w.Poll()
+	c.Assert(err, IsNil)
+	c.Assert(commit, Equals, "")
+	commit5 := commitManifest(c, repo, `{"nodes": []}`)
+	commit, _, err = w.Poll()
+	c.Assert(err, IsNil)
+	c.Assert(commit, Equals, commit5)
+}
@@ -0,0 +1,164 @@
+package gitops
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/contiv/errored"
+)
+
+// NodeSpec denotes the desired state of a node in the manifest
+type NodeSpec struct {
+	Name      string `json:"name"`
+	HostGroup string `json:"host_group"`
+}
+
+// Manifest denotes the declarative state of the cluster. The nodes listed in the
+// manifest are commissioned in their host-group, while the commissioned nodes that are
+// not listed are decommissioned.
+type Manifest struct {
+	// ExtraVars are the ansible variables used for the jobs applying the manifest
+	ExtraVars map[string]interface{} `json:"extra_vars,omitempty"`
+	Nodes     []NodeSpec             `json:"nodes"`
+}
+
+// ParseManifest parses and validates the json encoded manifest
+func ParseManifest(data []byte) (*Manifest, error) {
+	m := &Manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, errored.Errorf("failed to parse manifest. Error: %v", err)
+	}
+	seen := map[string]bool{}
+	for _, n := range m.Nodes {
+		if n.Name == "" {
+			return nil, errored.Errorf("a node in manifest doesn't have a name")
+		}
+		if n.HostGroup == "" {
+			return nil, errored.Errorf("node %q in manifest doesn't have a host-group", n.Name)
+		}
+		if seen[n.Name] {
+			return nil, errored.Errorf("node %q is listed more than once in manifest", n.Name)
+		}
+		seen[n.Name] = true
+	}
+	return m, nil
+}
+
+// NodeState denotes the current state of a node in the cluster
+type NodeState struct {
+	// Commissioned is true if the node is commissioned
+	Commissioned bool
+	// HostGroup is the host-group of a commissioned node
+	HostGroup string
+}
+
+// Action enumerates the workflows that are run to apply a manifest
+type Action string
+
+const (
+	// Commission action commissions the nodes in the host-group
+	Commission Action = "commission"
+	// Update action moves the commissioned nodes to the host-group
+	Update Action = "update"
+	// Decommission action decommissions the nodes
+	Decommission Action = "decommission"
+)
+
+// Step denotes a single workflow run of a plan
+type Step struct {
+	Action    Action
+	Nodes     []string
+	HostGroup string
+}
+
+// Plan denotes the steps to bring a cluster to the state declared in a manifest
+type Plan struct {
+	Steps []Step
+	// Skipped are the nodes in the manifest that are not in the cluster's current state,
+	// like the nodes that are not yet discovered. These are not acted on
+	Skipped []string
+}
+
+// ComputePlan returns the plan to bring the cluster from the current state to the
+// state in the manifest. The nodes are commissioned first, a step per host-group
+// in the order of the host-group names, followed by the host-group updates and the
+// decommissioning of the nodes removed from the manifest. The nodes that are not in
+// the current state are skipped, as are the nodes that are neither in the manifest
+// nor commissioned.
+func ComputePlan(m *Manifest, current map[string]NodeState) *Plan {
+	p := &Plan{}
+	commission := map[string][]string{}
+	update := map[string][]string{}
+	listed := map[string]bool{}
+	for _, n := range m.Nodes {
+		listed[n.Name] = true
+		state, ok := current[n.Name]
+		switch {
+		case !ok:
+			p.Skipped = append(p.Skipped, n.Name)
+		case !state.Commissioned:
+			commission[n.HostGroup] = append(commission[n.HostGroup], n.Name)
+		case state.HostGroup != n.HostGroup:
+			update[n.HostGroup] = append(update[n.HostGroup], n.Name)
+		}
+	}
+	decommission := []string{}
+	for name, state := range current {
+		if !listed[name] && state.Commissioned {
+			decommission = append(decommission, name)
+		}
+	}
+
+	p.Steps = append(p.Steps, groupSteps(Commission, commission)...)
+	p.Steps = append(p.Steps, groupSteps(Update, update)...)
+	if len(decommission) > 0 {
+		sort.Strings(decommission)
+		p.Steps = append(p.Steps, Step{Action: Decommission, Nodes: decommission})
+	}
+	sort.Strings(p.Skipped)
+	return p
+}
+
+// CheckDecommission returns an error if the plan decommissions all the commissioned
+// nodes in the current state, or more than the fraction of them. It guards against
+// an empty or a mis-pointed manifest tearing down the cluster.
+func CheckDecommission(p *Plan, current map[string]NodeState, maxFraction float64) error {
+	decommission := 0
+	for _, step := range p.Steps {
+		if step.Action == Decommission {
+			decommission += len(step.Nodes)
+		}
+	}
+	if decommission == 0 {
+		return nil
+	}
+	commissioned := 0
+	for _, state := range current {
+		if state.Commissioned {
+			commissioned++
+		}
+	}
+	if decommission >= commissioned {
+		return errored.Errorf("refusing to decommission all the %d commissioned node(s)", commissioned)
+	}
+	if float64(decommission) > maxFraction*float64(commissioned) {
+		return errored.Errorf("refusing to decommission %d of the %d commissioned node(s), it's more than the max decommission fraction %v",
+			decommission, commissioned, maxFraction)
+	}
+	return nil
+}
+
+// groupSteps returns a step of the action per host-group, in the order of host-group names
+func groupSteps(action Action, nodes map[string][]string) []Step {
+	groups := []string{}
+	for group := range nodes {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	steps := []Step{}
+	for _, group := range groups {
+		sort.Strings(nodes[group])
+		steps = append(steps, Step{Action: action, Nodes: nodes[group], HostGroup: group})
+	}
+	return steps
+}
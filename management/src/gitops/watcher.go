@@ -0,0 +1,184 @@
+package gitops
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/contiv/errored"
+)
+
+// Config denotes the configuration of the git repository that is watched for manifests
+type Config struct {
+	// Repo is the url of the git repository
+	Repo   string `json:"repo"`
+	Branch string `json:"branch"`
+	// ManifestPath is the path of the manifest file in the repository
+	ManifestPath string `json:"manifest_path"`
+	// WorkDir is the local directory the repository is fetched in
+	WorkDir          string `json:"work_dir"`
+	PollIntervalSecs int    `json:"poll_interval_secs"`
+	// MaxDecommissionFraction is the max fraction of the commissioned nodes that
+	// a commit may decommission. A commit decommissioning all of them is always refused
+	MaxDecommissionFraction float64 `json:"max_decommission_fraction"`
+}
+
+// lastCommitFile is the file in the work directory that records the last applied
+// commit, so that it is not applied again after a restart
+const lastCommitFile = "last_applied_commit"
+
+// DefaultConfig returns the default values for the unset configuration
+func DefaultConfig() Config {
+	return Config{
+		Branch:                  "master",
+		ManifestPath:            "cluster.json",
+		WorkDir:                 "/var/lib/clusterm/gitops",
+		PollIntervalSecs:        60,
+		MaxDecommissionFraction: 0.5,
+	}
+}
+
+// ApplyCallback is called with a new commit of the branch and it's manifest
+type ApplyCallback func(commit string, m *Manifest)
+
+// Watcher polls the branch of the git repository for new commits
+type Watcher struct {
+	config     Config
+	lastCommit string
+}
+
+// NewWatcher initializes and returns an instance of watcher
+func NewWatcher(config Config) *Watcher {
+	defaults := DefaultConfig()
+	if config.Branch == "" {
+		config.Branch = defaults.Branch
+	}
+	if config.ManifestPath == "" {
+		config.ManifestPath = defaults.ManifestPath
+	}
+	if config.WorkDir == "" {
+		config.WorkDir = defaults.WorkDir
+	}
+	if config.PollIntervalSecs <= 0 {
+		config.PollIntervalSecs = defaults.PollIntervalSecs
+	}
+	if config.MaxDecommissionFraction <= 0 {
+		config.MaxDecommissionFraction = defaults.MaxDecommissionFraction
+	}
+	w := &Watcher{
+		config: config,
+	}
+	w.loadLastCommit()
+	return w
+}
+
+// Config returns the configuration of the watcher, with the defaults filled in
+func (w *Watcher) Config() Config {
+	return w.config
+}
+
+// loadLastCommit reads the last applied commit recorded in the work directory, if any
+func (w *Watcher) loadLastCommit() {
+	data, err := ioutil.ReadFile(filepath.Join(w.config.WorkDir, lastCommitFile))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logrus.Warnf("failed to read the last applied gitops commit. Error: %v", err)
+		}
+		return
+	}
+	w.lastCommit = strings.TrimSpace(string(data))
+}
+
+// saveLastCommit records the commit as the last applied one in the work directory
+func (w *Watcher) saveLastCommit(commit string) {
+	if err := ioutil.WriteFile(filepath.Join(w.config.WorkDir, lastCommitFile), []byte(commit+"\n"),
+		0600); err != nil {
+		logrus.Errorf("failed to record the last applied gitops commit %q. Error: %v", commit, err)
+	}
+}
+
+// git runs the git command in the work directory and returns it's output
+func (w *Watcher) git(args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Dir = w.config.WorkDir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, errored.Errorf("git %s failed. Error: %v, Output: %s", args[0], err,
+			strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// fetch fetches the branch of the repository in the work directory, initializing
+// the local repository the first time
+func (w *Watcher) fetch() error {
+	if _, err := os.Stat(filepath.Join(w.config.WorkDir, ".git")); os.IsNotExist(err) {
+		if err := os.MkdirAll(w.config.WorkDir, 0700); err != nil {
+			return errored.Errorf("failed to create gitops work directory %q. Error: %v", w.config.WorkDir, err)
+		}
+		if _, err := w.git("init"); err != nil {
+			return err
+		}
+	}
+	_, err := w.git("fetch", "--quiet", w.config.Repo,
+		"+refs/heads/"+w.config.Branch+":refs/remotes/origin/"+w.config.Branch)
+	return err
+}
+
+// Poll fetches the branch and returns it's head commit and the manifest at that
+// commit. The commit is returned as empty when the head hasn't changed since the last
+// poll. Only the manifest at the head is returned when more than one commit is
+// fetched, as that is the latest declared state.
+func (w *Watcher) Poll() (string, *Manifest, error) {
+	if err := w.fetch(); err != nil {
+		return "", nil, err
+	}
+	out, err := w.git("rev-parse", "refs/remotes/origin/"+w.config.Branch)
+	if err != nil {
+		return "", nil, err
+	}
+	commit := strings.TrimSpace(string(out))
+	if commit == w.lastCommit {
+		return "", nil, nil
+	}
+	// a commit is polled once, even if it's manifest is invalid. A later commit is
+	// expected to fix it
+	w.lastCommit = commit
+
+	data, err := w.git("show", commit+":"+w.config.ManifestPath)
+	if err != nil {
+		return commit, nil, err
+	}
+	m, err := ParseManifest(data)
+	if err != nil {
+		return commit, nil, err
+	}
+	return commit, m, nil
+}
+
+// Watch polls the branch at the configured interval and calls the callback for
+// every new commit with a valid manifest. The callback is expected to apply the
+// manifest before returning, after which the commit is recorded as applied. This
+// function blocks.
+func (w *Watcher) Watch(cb ApplyCallback) {
+	for {
+		commit, m, err := w.Poll()
+		if err != nil {
+			logrus.Errorf("failed to poll the gitops repository %q. Commit: %q, Error: %v", w.config.Repo, commit, err)
+		} else if commit != "" {
+			logrus.Infof("applying the manifest at commit %q of gitops repository %q", commit, w.config.Repo)
+			cb(commit, m)
+		}
+		if commit != "" {
+			w.saveLastCommit(commit)
+		}
+		time.Sleep(time.Duration(w.config.PollIntervalSecs) * time.Second)
+	}
+}
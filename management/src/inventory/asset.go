@@ -2,10 +2,12 @@ package inventory
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/Sirupsen/logrus"
-	"github.com/contiv/errored"
+	"github.com/contiv/cluster/management/src/clustererr"
 )
 
 // StateDescription is description of various assets states
@@ -16,10 +18,35 @@ var StateDescription = map[AssetState]string{
 }
 
 var (
-	errAssetExists    = func(tag string) error { return errored.Errorf("asset %q already exists", tag) }
-	errAssetNotExists = func(tag string) error { return errored.Errorf("asset %q doesn't exists", tag) }
+	errAssetExists = func(tag string) error {
+		return clustererr.NewForNode(clustererr.Conflict, tag, "asset %q already exists", tag)
+	}
+	errAssetNotExists = func(tag string) error {
+		return clustererr.NewForNode(clustererr.Validation, tag, "asset %q doesn't exists", tag)
+	}
 )
 
+// AssetErrors are the errors of the assets that failed in a batch update, keyed by asset name
+type AssetErrors map[string]error
+
+func (e AssetErrors) Error() string {
+	names := []string{}
+	for name := range e {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	errs := []string{}
+	for _, name := range names {
+		errs = append(errs, fmt.Sprintf("%s: %v", name, e[name]))
+	}
+	return fmt.Sprintf("%d asset(s) failed. Errors: [%s]", len(e), strings.Join(errs, "; "))
+}
+
+// Errors returns the errors keyed by asset name
+func (e AssetErrors) Errors() map[string]error {
+	return e
+}
+
 // AssetStatusVals maps the status strings to corresponding enumerated values
 var AssetStatusVals = map[string]AssetStatus{
 	Incomplete.String():     Incomplete,
@@ -107,10 +134,13 @@ type Asset struct {
 	prevStatus AssetStatus
 	state      AssetState
 	prevState  AssetState
+	attributes map[string]string
 }
 
 // NewAssetWithState creates a new asset in the inventory in a discovered state and returns it.
-func NewAssetWithState(client SubsysClient, name string, status AssetStatus, state AssetState) *Asset {
+// The attributes are the ones associated with the asset in the inventory, if any.
+func NewAssetWithState(client SubsysClient, name string, status AssetStatus, state AssetState,
+	attributes map[string]string) *Asset {
 	return &Asset{
 		client:     client,
 		name:       name,
@@ -118,6 +148,7 @@ func NewAssetWithState(client SubsysClient, name string, status AssetStatus, sta
 		prevStatus: Incomplete,
 		state:      state,
 		prevState:  Unknown,
+		attributes: attributes,
 	}
 }
 
@@ -133,12 +164,12 @@ func NewAsset(client SubsysClient, name string) (*Asset, error) {
 	}
 
 	if err := a.client.CreateAsset(name, a.status.String()); err != nil {
-		return nil, err
+		return nil, clustererr.Wrap(clustererr.Inventory, name, err)
 	}
 
 	if err := a.client.SetAssetStatus(name, a.status.String(), a.state.String(), StateDescription[a.state]); err != nil {
 		//XXX: should we delete the asset here?
-		return nil, err
+		return nil, clustererr.Wrap(clustererr.Inventory, name, err)
 	}
 
 	logrus.Debugf("created asset: %+v", a)
@@ -153,24 +184,39 @@ func (a *Asset) SetStatus(status AssetStatus, state AssetState) error {
 		return nil
 	}
 
-	if _, ok := lifecycleStatus[a.status][status]; !ok && a.status != status {
-		return errored.Errorf("transition from %q to %q is not allowed", a.status, status)
+	if err := a.checkTransition(status, state); err != nil {
+		return err
 	}
 
-	if _, ok := lifecycleStates[status][state]; !ok {
-		return errored.Errorf("%q is not a valid state when asset is in %q status", state, status)
+	if err := a.client.SetAssetStatus(a.name, status.String(), state.String(), StateDescription[state]); err != nil {
+		return clustererr.Wrap(clustererr.Inventory, a.name, err)
 	}
 
-	if err := a.client.SetAssetStatus(a.name, status.String(), state.String(), StateDescription[state]); err != nil {
-		return err
+	a.updateStatus(status, state)
+
+	return nil
+}
+
+// checkTransition returns an error if the asset's lifecycle doesn't allow
+// moving to the status and state
+func (a *Asset) checkTransition(status AssetStatus, state AssetState) error {
+	if _, ok := lifecycleStatus[a.status][status]; !ok && a.status != status {
+		return clustererr.NewForNode(clustererr.Conflict, a.name, "transition from %q to %q is not allowed", a.status, status)
+	}
+
+	if _, ok := lifecycleStates[status][state]; !ok {
+		return clustererr.NewForNode(clustererr.Conflict, a.name, "%q is not a valid state when asset is in %q status",
+			state, status)
 	}
+	return nil
+}
 
+// updateStatus records the status and state of the asset, once it is set in the inventory
+func (a *Asset) updateStatus(status AssetStatus, state AssetState) {
 	a.prevStatus = a.status
 	a.prevState = a.state
 	a.status = status
 	a.state = state
-
-	return nil
 }
 
 // GetStatus returns the current status and state of an asset.
@@ -183,8 +229,14 @@ func (a *Asset) GetTag() string {
 	return a.name
 }
 
+// GetAttributes returns the attributes associated with the asset in inventory
+func (a *Asset) GetAttributes() map[string]string {
+	return a.attributes
+}
+
 // MarshalJSON implements the json marshaller for asset. It is done this way
 // than making the fields public inorder to safeguard against direct state interpolation.
+// The attributes are not encoded as they may carry credentials like the BMC password.
 func (a *Asset) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
 		Name       string `json:"name"`
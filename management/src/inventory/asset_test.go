@@ -200,3 +200,61 @@ func (s *inventorySuite) TestSetStatusSetFailure(c *C) {
 	c.Assert(err, NotNil)
 	c.Assert(asset, DeepEquals, eAsset)
 }
+
+// batchClient is a subsystem client that records the batched status updates
+type batchClient struct {
+	*mock.MockSubsysClient
+	batches [][]AssetStatusUpdate
+	err     error
+}
+
+func (bc *batchClient) SetAssetsStatus(updates []AssetStatusUpdate) error {
+	bc.batches = append(bc.batches, updates)
+	return bc.err
+}
+
+func (s *inventorySuite) TestSetAssetsState(c *C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mClient := mock.NewMockSubsysClient(ctrl)
+	ci := NewGeneralSubsys(mClient)
+	ci.RestoreAsset("foo", NewAssetWithState(mClient, "foo", Allocated, Discovered, nil))
+	ci.RestoreAsset("bar", NewAssetWithState(mClient, "bar", Unallocated, Discovered, nil))
+	ci.RestoreAsset("baz", NewAssetWithState(mClient, "baz", Unallocated, Disappeared, nil))
+
+	// without batch support the assets are updated one at a time, and the
+	// assets already in the state are skipped
+	mClient.EXPECT().SetAssetStatus("foo", Allocated.String(), Disappeared.String(),
+		StateDescription[Disappeared])
+	mClient.EXPECT().SetAssetStatus("bar", Unallocated.String(), Disappeared.String(),
+		StateDescription[Disappeared]).Return(errored.Errorf("test failure"))
+	err := ci.SetAssetsDisappeared([]string{"foo", "bar", "baz", "blah"})
+	c.Assert(err, FitsTypeOf, AssetErrors{})
+	errs := err.(AssetErrors)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["bar"], ErrorMatches, "test failure")
+	c.Assert(errs["blah"], ErrorMatches, ".*doesn't exists.*")
+	for name, exptdState := range map[string]AssetState{"foo": Disappeared, "bar": Discovered, "baz": Disappeared} {
+		_, state := ci.GetAsset(name).GetStatus()
+		c.Assert(state, Equals, exptdState, Commentf("asset: %s", name))
+	}
+
+	// with batch support the assets are updated in a single write
+	bc := &batchClient{MockSubsysClient: mClient}
+	ci.client = bc
+	c.Assert(ci.SetAssetsDiscovered([]string{"foo", "bar", "baz"}), IsNil)
+	c.Assert(bc.batches, DeepEquals, [][]AssetStatusUpdate{{
+		{Tag: "foo", Status: Allocated.String(), State: Discovered.String(), Reason: StateDescription[Discovered]},
+		{Tag: "baz", Status: Unallocated.String(), State: Discovered.String(), Reason: StateDescription[Discovered]},
+	}})
+	_, state := ci.GetAsset("baz").GetStatus()
+	c.Assert(state, Equals, Discovered)
+
+	// a failed batch fails all of it's assets
+	bc.err = errored.Errorf("test failure")
+	err = ci.SetAssetsDisappeared([]string{"foo", "bar"})
+	c.Assert(err, ErrorMatches, `2 asset\(s\) failed. Errors: \[bar: test failure; foo: test failure\]`)
+	_, state = ci.GetAsset("foo").GetStatus()
+	c.Assert(state, Equals, Discovered)
+}
@@ -22,7 +22,7 @@ func NewBoltdbSubsys(config boltdb.Config) (*inventory.GeneralSubsys, error) {
 	assets1 := assets.([]boltdb.Asset)
 	for _, asset := range assets1 {
 		a := inventory.NewAssetWithState(client, asset.Name, inventory.AssetStatusVals[asset.Status],
-			inventory.AssetStateVals[asset.State])
+			inventory.AssetStateVals[asset.State], asset.Attributes)
 		if err := subsys.RestoreAsset(asset.Name, a); err != nil {
 			logrus.Infof("failed to restore asset %q. Error: %v", asset.Name, err)
 			continue
@@ -7,6 +7,22 @@ import (
 	"github.com/contiv/errored"
 )
 
+// assetAttributes returns the attributes of a collins asset. The BMC related
+// attributes are picked from the asset's IPMI info in collins, if it is set.
+func assetAttributes(asset collins.Asset) map[string]string {
+	attrs := asset.Attributes
+	if asset.IPMI == nil || asset.IPMI.Address == "" {
+		return attrs
+	}
+	if attrs == nil {
+		attrs = make(map[string]string)
+	}
+	attrs[inventory.AttrBMCAddress] = asset.IPMI.Address
+	attrs[inventory.AttrBMCUser] = asset.IPMI.Username
+	attrs[inventory.AttrBMCPassword] = asset.IPMI.Password
+	return attrs
+}
+
 // NewCollinsSubsys initializes and return an instance of collins based inventory Subsys
 func NewCollinsSubsys(config collins.Config) (*inventory.GeneralSubsys, error) {
 	client := collins.NewClientFromConfig(config)
@@ -27,7 +43,7 @@ func NewCollinsSubsys(config collins.Config) (*inventory.GeneralSubsys, error) {
 	assets1 := assets.([]collins.Asset)
 	for _, asset := range assets1 {
 		a := inventory.NewAssetWithState(client, asset.Tag, inventory.AssetStatusVals[asset.Status],
-			inventory.AssetStateVals[asset.State.Name])
+			inventory.AssetStateVals[asset.State.Name], assetAttributes(asset))
 		if err := subsys.RestoreAsset(asset.Tag, a); err != nil {
 			logrus.Infof("failed to restore asset %q. Error: %v", asset.Tag, err)
 			continue
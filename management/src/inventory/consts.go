@@ -51,3 +51,13 @@ const (
 	// Disappeared state denotes that host has disappeared from monitoring subsystem.
 	Disappeared
 )
+
+const (
+	// AttrBMCAddress is the asset attribute that holds the address of the node's
+	// baseboard management controller (BMC)
+	AttrBMCAddress = "bmc_address"
+	// AttrBMCUser is the asset attribute that holds the user name for accessing the BMC
+	AttrBMCUser = "bmc_user"
+	// AttrBMCPassword is the asset attribute that holds the password for accessing the BMC
+	AttrBMCPassword = "bmc_password"
+)
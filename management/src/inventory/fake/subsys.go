@@ -0,0 +1,120 @@
+package fake
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/contiv/cluster/management/src/inventory"
+	"github.com/contiv/errored"
+)
+
+// Config denotes the configuration for the fake inventory
+type Config struct {
+	// Assets are the assets that are restored in the inventory on start. For
+	// instance, to simulate a cluster with commissioned nodes.
+	Assets []Asset `json:"assets,omitempty"`
+}
+
+// Asset denotes the asset related information as kept in the fake inventory
+type Asset struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	State     string `json:"state"`
+	StateDesc string `json:"state_desc"`
+	// Attributes are not set by clusterm, but can be provisioned for an asset
+	// before it is discovered. For instance, the information to access a node's BMC.
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// Client implements an in-memory inventory subsystem client. The assets are
+// lost when the process exits.
+type Client struct {
+	sync.Mutex
+	assets map[string]*Asset
+}
+
+// NewClient initializes and returns an empty fake inventory client
+func NewClient() *Client {
+	return &Client{
+		assets: make(map[string]*Asset),
+	}
+}
+
+// GetAllAssets returns a copy of all the assets
+func (c *Client) GetAllAssets() (interface{}, error) {
+	c.Lock()
+	defer c.Unlock()
+	assets := []Asset{}
+	for _, a := range c.assets {
+		assets = append(assets, *a)
+	}
+	return assets, nil
+}
+
+// CreateAsset creates an asset with specified tag and status
+func (c *Client) CreateAsset(tag, status string) error {
+	c.Lock()
+	defer c.Unlock()
+	c.assets[tag] = &Asset{Name: tag, Status: status}
+	return nil
+}
+
+// CreateState is a noop for fake inventory
+func (c *Client) CreateState(name, description, status string) error {
+	return nil
+}
+
+// AddAssetLog is a noop for fake inventory
+func (c *Client) AddAssetLog(tag, mtype, message string) error {
+	return nil
+}
+
+// setAssetStatus sets the status of an asset. It expects the lock to be held
+func (c *Client) setAssetStatus(tag, status, state, reason string) error {
+	a, ok := c.assets[tag]
+	if !ok {
+		return errored.Errorf("No asset found for name: %s", tag)
+	}
+	a.Status = status
+	a.State = state
+	a.StateDesc = reason
+	return nil
+}
+
+// SetAssetStatus sets the status of an asset
+func (c *Client) SetAssetStatus(tag, status, state, reason string) error {
+	c.Lock()
+	defer c.Unlock()
+	return c.setAssetStatus(tag, status, state, reason)
+}
+
+// SetAssetsStatus sets the status of multiple assets
+func (c *Client) SetAssetsStatus(updates []inventory.AssetStatusUpdate) error {
+	c.Lock()
+	defer c.Unlock()
+	for _, u := range updates {
+		if err := c.setAssetStatus(u.Tag, u.Status, u.State, u.Reason); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewFakeSubsys initializes and return an instance of an in-memory inventory
+// subsystem, that is useful to simulate a cluster
+func NewFakeSubsys(config Config) (*inventory.GeneralSubsys, error) {
+	client := NewClient()
+	subsys := inventory.NewGeneralSubsys(client)
+
+	// restore the configured assets
+	for _, asset := range config.Assets {
+		a := asset
+		client.assets[a.Name] = &a
+		if err := subsys.RestoreAsset(a.Name, inventory.NewAssetWithState(client, a.Name,
+			inventory.AssetStatusVals[a.Status], inventory.AssetStateVals[strings.ToUpper(a.State)], a.Attributes)); err != nil {
+			return nil, err
+		}
+	}
+
+	return subsys, nil
+}
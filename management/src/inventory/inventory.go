@@ -13,6 +13,10 @@ type Subsys interface {
 	SetAssetDiscovered(name string) error
 	//SetAssetDisappeared sets an asset state to disappeared
 	SetAssetDisappeared(name string) error
+	//SetAssetsDiscovered sets the state of multiple assets to discovered, in a batch
+	SetAssetsDiscovered(names []string) error
+	//SetAssetsDisappeared sets the state of multiple assets to disappeared, in a batch
+	SetAssetsDisappeared(names []string) error
 	//SetAssetProvisioning sets an asset state to provisioning
 	SetAssetProvisioning(name string) error
 	//SetAssetCommissioned sets an asset state to commissioned (aka allocated)
@@ -40,12 +44,28 @@ type SubsysClient interface {
 	SetAssetStatus(tag, status, state, reason string) error
 }
 
+// AssetStatusUpdate is the status and state to be set for an asset
+type AssetStatusUpdate struct {
+	Tag    string
+	Status string
+	State  string
+	Reason string
+}
+
+// BatchSubsysClient is implemented by the inventory subsystem clients that can
+// set the status of multiple assets in a single write
+type BatchSubsysClient interface {
+	SetAssetsStatus(updates []AssetStatusUpdate) error
+}
+
 // SubsysAsset denotes a single asset in inventory subsystem
 type SubsysAsset interface {
 	//GetStatus returns the current status of the asset
 	GetStatus() (AssetStatus, AssetState)
 	//GetTag returns the inventory tag of the asset
 	GetTag() string
+	//GetAttributes returns the attributes associated with the asset in inventory
+	GetAttributes() map[string]string
 	//SubsysAsset shall satisfy the json marshaller interface to encode asset's info in json
 	json.Marshaler
 }
@@ -1,5 +1,7 @@
 package inventory
 
+import "github.com/contiv/cluster/management/src/clustererr"
+
 // GeneralSubsys implements the inventory sub-system. It is instantiated using
 // the New* methods of specific subsystems like collins, boltdb and so on
 type GeneralSubsys struct {
@@ -61,6 +63,76 @@ func (ci *GeneralSubsys) SetAssetDisappeared(name string) error {
 	return ci.assets[name].SetStatus(status, Disappeared)
 }
 
+//SetAssetsDiscovered sets the state of multiple assets to discovered, in a batch
+func (ci *GeneralSubsys) SetAssetsDiscovered(names []string) error {
+	return ci.setAssetsState(names, Discovered)
+}
+
+//SetAssetsDisappeared sets the state of multiple assets to disappeared, in a batch
+func (ci *GeneralSubsys) SetAssetsDisappeared(names []string) error {
+	return ci.setAssetsState(names, Disappeared)
+}
+
+// setAssetsState sets the state of the assets, retaining their status. The
+// assets are updated in a single write if the client supports it.
+func (ci *GeneralSubsys) setAssetsState(names []string, state AssetState) error {
+	errs := AssetErrors{}
+	assets := []*Asset{}
+	updates := []AssetStatusUpdate{}
+	for _, name := range names {
+		a, ok := ci.assets[name]
+		if !ok {
+			errs[name] = errAssetNotExists(name)
+			continue
+		}
+		status, curState := a.GetStatus()
+		if curState == state {
+			continue
+		}
+		if err := a.checkTransition(status, state); err != nil {
+			errs[name] = err
+			continue
+		}
+		assets = append(assets, a)
+		updates = append(updates, AssetStatusUpdate{
+			Tag:    name,
+			Status: status.String(),
+			State:  state.String(),
+			Reason: StateDescription[state],
+		})
+	}
+
+	if bc, ok := ci.client.(BatchSubsysClient); ok && len(updates) > 0 {
+		if err := bc.SetAssetsStatus(updates); err != nil {
+			for _, a := range assets {
+				errs[a.name] = clustererr.Wrap(clustererr.Inventory, a.name, err)
+			}
+			assets = nil
+		}
+	} else {
+		succeeded := []*Asset{}
+		for i, a := range assets {
+			u := updates[i]
+			if err := ci.client.SetAssetStatus(u.Tag, u.Status, u.State, u.Reason); err != nil {
+				errs[a.name] = clustererr.Wrap(clustererr.Inventory, a.name, err)
+				continue
+			}
+			succeeded = append(succeeded, a)
+		}
+		assets = succeeded
+	}
+
+	for _, a := range assets {
+		status, _ := a.GetStatus()
+		a.updateStatus(status, state)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
 //SetAssetProvisioning sets an asset state to provisioning
 func (ci *GeneralSubsys) SetAssetProvisioning(name string) error {
 	if _, ok := ci.assets[name]; !ok {
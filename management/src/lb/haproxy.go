@@ -0,0 +1,108 @@
+package lb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/contiv/errored"
+)
+
+// HAProxyConfig denotes the configuration for HAProxy based load balancer subsystem.
+// The pool members are managed as dynamic servers through HAProxy's runtime API.
+type HAProxyConfig struct {
+	// Socket is the runtime API (stats socket) address. A path denotes a unix
+	// socket, else a tcp address like 127.0.0.1:9999 is expected
+	Socket string `json:"socket"`
+	// Backend is the backend that the nodes are added to as servers
+	Backend string `json:"backend"`
+	// Port is the port where nodes serve the traffic
+	Port int `json:"port"`
+}
+
+// HAProxySubsys implements the load balancer subsystem for HAProxy
+type HAProxySubsys struct {
+	config HAProxyConfig
+}
+
+// NewHAProxySubsys initializes and returns an instance of HAProxy based load balancer subsystem
+func NewHAProxySubsys(config HAProxyConfig) *HAProxySubsys {
+	return &HAProxySubsys{
+		config: config,
+	}
+}
+
+// runCmd runs a command on the runtime API and returns the response
+func (s *HAProxySubsys) runCmd(cmd string) (string, error) {
+	network := "tcp"
+	if strings.HasPrefix(s.config.Socket, "/") {
+		network = "unix"
+	}
+	conn, err := net.DialTimeout(network, s.config.Socket, 10*time.Second)
+	if err != nil {
+		return "", errored.Errorf("failed to connect to haproxy runtime api at %q. Error: %v", s.config.Socket, err)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(30 * time.Second)); err != nil {
+		return "", err
+	}
+
+	// in non-interactive mode haproxy closes the connection after responding to the command
+	if _, err := fmt.Fprintf(conn, "%s\n", cmd); err != nil {
+		return "", errored.Errorf("failed to send command %q to haproxy. Error: %v", cmd, err)
+	}
+	resp, err := ioutil.ReadAll(conn)
+	if err != nil {
+		return "", errored.Errorf("failed to read response of command %q from haproxy. Error: %v", cmd, err)
+	}
+	logrus.Debugf("haproxy command %q. Response: %q", cmd, resp)
+	return strings.TrimSpace(string(resp)), nil
+}
+
+// runCmdExpect runs a command on the runtime API and returns error if the response
+// doesn't start with one of the expected responses. An empty expected response
+// matches only an empty response.
+func (s *HAProxySubsys) runCmdExpect(cmd string, expected ...string) error {
+	resp, err := s.runCmd(cmd)
+	if err != nil {
+		return err
+	}
+	for _, e := range expected {
+		if (e == "" && resp == "") || (e != "" && strings.HasPrefix(resp, e)) {
+			return nil
+		}
+	}
+	return errored.Errorf("haproxy command %q failed. Response: %q", cmd, resp)
+}
+
+func (s *HAProxySubsys) server(m *Member) string {
+	return s.config.Backend + "/" + m.Name
+}
+
+// Register implements the register interface of load balancer subsystem
+func (s *HAProxySubsys) Register(m *Member) error {
+	if err := s.runCmdExpect(fmt.Sprintf("add server %s %s", s.server(m),
+		net.JoinHostPort(m.Addr, fmt.Sprintf("%d", s.config.Port))),
+		"New server registered", "Already exists"); err != nil {
+		return err
+	}
+	// a newly added server starts in maintenance mode
+	return s.runCmdExpect(fmt.Sprintf("set server %s state ready", s.server(m)), "")
+}
+
+// Drain implements the drain interface of load balancer subsystem
+func (s *HAProxySubsys) Drain(m *Member) error {
+	return s.runCmdExpect(fmt.Sprintf("set server %s state drain", s.server(m)), "")
+}
+
+// Deregister implements the deregister interface of load balancer subsystem
+func (s *HAProxySubsys) Deregister(m *Member) error {
+	// a server needs to be in maintenance mode before it can be deleted
+	if err := s.runCmdExpect(fmt.Sprintf("set server %s state maint", s.server(m)), ""); err != nil {
+		return err
+	}
+	return s.runCmdExpect(fmt.Sprintf("del server %s", s.server(m)), "Server deleted")
+}
@@ -0,0 +1,29 @@
+package lb
+
+import (
+	"fmt"
+)
+
+// Member denotes a node that is a member of the load balancer pool
+type Member struct {
+	Name string
+	Addr string
+}
+
+// String returns the description of the member
+func (m *Member) String() string {
+	return fmt.Sprintf("%s(%s)", m.Name, m.Addr)
+}
+
+// Subsys provides the following services to the cluster manager:
+// - Interface to manage the membership of nodes in a load balancer pool.
+type Subsys interface {
+	// Register adds the member to the pool and makes it ready to receive traffic.
+	// Registering a member that is already in the pool makes it ready again.
+	Register(m *Member) error
+	// Drain stops new traffic from being sent to the member, while existing
+	// connections are allowed to complete
+	Drain(m *Member) error
+	// Deregister removes the member from the pool
+	Deregister(m *Member) error
+}
@@ -0,0 +1,91 @@
+// +build unittest
+
+package lb
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type lbSuite struct {
+}
+
+var _ = Suite(&lbSuite{})
+
+// fakeHAProxy serves the runtime API with the specified responses and records the commands received
+func fakeHAProxy(c *C, responses map[string]string) (string, *[]string, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	cmds := &[]string{}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			cmd, _ := bufio.NewReader(conn).ReadString('\n')
+			cmd = strings.TrimSpace(cmd)
+			*cmds = append(*cmds, cmd)
+			conn.Write([]byte(responses[strings.Fields(cmd)[0]+" "+strings.Fields(cmd)[1]]))
+			conn.Close()
+		}
+	}()
+	return l.Addr().String(), cmds, func() { l.Close() }
+}
+
+func (s *lbSuite) TestHAProxyRegisterDrainDeregister(c *C) {
+	addr, cmds, stop := fakeHAProxy(c, map[string]string{
+		"add server": "New server registered.\n",
+		"del server": "Server deleted.\n",
+	})
+	defer stop()
+
+	subsys := NewHAProxySubsys(HAProxyConfig{Socket: addr, Backend: "web", Port: 80})
+	m := &Member{Name: "node1", Addr: "1.2.3.4"}
+	c.Assert(subsys.Register(m), IsNil)
+	c.Assert(subsys.Drain(m), IsNil)
+	c.Assert(subsys.Deregister(m), IsNil)
+	c.Assert(*cmds, DeepEquals, []string{
+		"add server web/node1 1.2.3.4:80",
+		"set server web/node1 state ready",
+		"set server web/node1 state drain",
+		"set server web/node1 state maint",
+		"del server web/node1",
+	})
+}
+
+func (s *lbSuite) TestHAProxyRegisterExisting(c *C) {
+	addr, _, stop := fakeHAProxy(c, map[string]string{
+		"add server": "Already exists a server with the same name in backend.\n",
+	})
+	defer stop()
+
+	subsys := NewHAProxySubsys(HAProxyConfig{Socket: addr, Backend: "web", Port: 80})
+	c.Assert(subsys.Register(&Member{Name: "node1", Addr: "1.2.3.4"}), IsNil)
+}
+
+func (s *lbSuite) TestHAProxyCommandFailure(c *C) {
+	addr, _, stop := fakeHAProxy(c, map[string]string{
+		"set server": "No such backend.\n",
+	})
+	defer stop()
+
+	subsys := NewHAProxySubsys(HAProxyConfig{Socket: addr, Backend: "web", Port: 80})
+	err := subsys.Drain(&Member{Name: "node1", Addr: "1.2.3.4"})
+	c.Assert(err, NotNil)
+	c.Assert(strings.Contains(err.Error(), "No such backend"), Equals, true)
+}
+
+func (s *lbSuite) TestTargetGroupArgs(c *C) {
+	subsys := NewTargetGroupSubsys(TargetGroupConfig{TargetGroupARN: "arn:tg", Port: 8080})
+	args := subsys.elbv2Args([]string{"wait", "target-deregistered"}, &Member{Name: "node1", Addr: "1.2.3.4"})
+	c.Assert(args, DeepEquals, []string{"elbv2", "wait", "target-deregistered", "--target-group-arn", "arn:tg",
+		"--targets", "Id=1.2.3.4,Port=8080"})
+}
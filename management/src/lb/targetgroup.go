@@ -0,0 +1,66 @@
+package lb
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/contiv/errored"
+)
+
+// TargetGroupConfig denotes the configuration for AWS target group based load
+// balancer subsystem. The nodes are registered as ip targets. The AWS credentials
+// are picked by the aws cli from it's environment or instance profile.
+type TargetGroupConfig struct {
+	TargetGroupARN string `json:"target_group_arn"`
+	// Port is the port where nodes serve the traffic
+	Port int `json:"port"`
+}
+
+// TargetGroupSubsys implements the load balancer subsystem for AWS target groups using the aws cli
+type TargetGroupSubsys struct {
+	config TargetGroupConfig
+}
+
+// NewTargetGroupSubsys initializes and returns an instance of AWS target group based load balancer subsystem
+func NewTargetGroupSubsys(config TargetGroupConfig) *TargetGroupSubsys {
+	return &TargetGroupSubsys{
+		config: config,
+	}
+}
+
+// elbv2Args returns the aws cli arguments to run the elbv2 command for the member
+func (s *TargetGroupSubsys) elbv2Args(cmd []string, m *Member) []string {
+	return append(append([]string{"elbv2"}, cmd...), "--target-group-arn", s.config.TargetGroupARN,
+		"--targets", fmt.Sprintf("Id=%s,Port=%d", m.Addr, s.config.Port))
+}
+
+func (s *TargetGroupSubsys) elbv2(m *Member, cmd ...string) error {
+	output, err := exec.Command("aws", s.elbv2Args(cmd, m)...).CombinedOutput()
+	if err != nil {
+		return errored.Errorf("aws elbv2 %v for member %s failed. Output: %s, Error: %v", cmd, m, output, err)
+	}
+	logrus.Debugf("aws elbv2 %v for member %s. Output: %s", cmd, m, output)
+	return nil
+}
+
+// Register implements the register interface of load balancer subsystem
+func (s *TargetGroupSubsys) Register(m *Member) error {
+	return s.elbv2(m, "register-targets")
+}
+
+// Drain implements the drain interface of load balancer subsystem. Deregistering
+// a target starts the connection draining in AWS, for the deregistration delay
+// configured on the target group.
+func (s *TargetGroupSubsys) Drain(m *Member) error {
+	return s.elbv2(m, "deregister-targets")
+}
+
+// Deregister implements the deregister interface of load balancer subsystem. It
+// waits for the target to be deregistered, following the drain.
+func (s *TargetGroupSubsys) Deregister(m *Member) error {
+	if err := s.Drain(m); err != nil {
+		return err
+	}
+	return s.elbv2(m, "wait", "target-deregistered")
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/contiv/cluster/management/src/netwriter"
+)
+
+// GraphiteConfig denotes the configuration for emitting metrics to graphite through
+// it's plaintext protocol over tcp
+type GraphiteConfig struct {
+	// Addr is the address of carbon's plaintext listener, like localhost:2003
+	Addr string `json:"addr"`
+	// Prefix is prepended to the metric names. It defaults to clusterm
+	Prefix string `json:"prefix"`
+}
+
+// GraphiteDriver implements the metrics driver for graphite. As graphite stores plain
+// values, the timers are stored in milliseconds and the counters as the count of one
+// occurrence; these are expected to be summarized by graphite's aggregation functions.
+type GraphiteDriver struct {
+	config GraphiteConfig
+	writer *netwriter.Writer
+}
+
+// NewGraphiteDriver initializes and returns an instance of graphite driver
+func NewGraphiteDriver(config GraphiteConfig) *GraphiteDriver {
+	if config.Prefix == "" {
+		config.Prefix = "clusterm"
+	}
+	return &GraphiteDriver{
+		config: config,
+		writer: netwriter.New("graphite", "tcp", config.Addr),
+	}
+}
+
+// line returns the graphite plaintext line for the metric
+func (d *GraphiteDriver) line(m *Metric) string {
+	return fmt.Sprintf("%s %s %d\n", prefixed(d.config.Prefix, m.Name),
+		strconv.FormatFloat(m.Value, 'f', -1, 64), m.Time.Unix())
+}
+
+// Send implements the send interface of metrics driver. The connection is reused
+// across metrics and is re-established on failure.
+func (d *GraphiteDriver) Send(m *Metric) error {
+	_, err := d.writer.Write([]byte(d.line(m)))
+	return err
+}
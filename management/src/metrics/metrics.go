@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Type enumerates the types of the emitted metrics
+type Type string
+
+const (
+	// Counter is a count of occurrences since the last emission
+	Counter Type = "c"
+	// Gauge is an instantaneous value
+	Gauge Type = "g"
+	// Timer is a duration, emitted in milliseconds
+	Timer Type = "ms"
+)
+
+// Metric denotes a single measurement that is emitted
+type Metric struct {
+	Name  string
+	Value float64
+	Type  Type
+	Time  time.Time
+}
+
+// Subsys provides the following services to the cluster manager:
+// - Interface to emit the metrics to a metrics collector.
+type Subsys interface {
+	// Incr emits a count of one for the counter
+	Incr(name string)
+	// Gauge emits the current value of the gauge
+	Gauge(name string, value int64)
+	// Timing emits the duration of the timer
+	Timing(name string, d time.Duration)
+}
+
+// Driver is implemented by the metrics collector specific drivers
+type Driver interface {
+	// Send emits the metric to the collector
+	Send(m *Metric) error
+}
+
+// queueSize is the number of metrics that can be pending before the newer metrics are dropped
+const queueSize = 1000
+
+// AsyncEmitter implements the metrics subsystem by emitting the metrics through the
+// driver, in the order they are recorded
+type AsyncEmitter struct {
+	driver Driver
+	queue  chan *Metric
+}
+
+// NewAsyncEmitter initializes and returns an instance of metrics subsystem
+func NewAsyncEmitter(driver Driver) *AsyncEmitter {
+	e := &AsyncEmitter{
+		driver: driver,
+		queue:  make(chan *Metric, queueSize),
+	}
+	go e.emitLoop()
+	return e
+}
+
+func (e *AsyncEmitter) emitLoop() {
+	for m := range e.queue {
+		if err := e.driver.Send(m); err != nil {
+			logrus.Errorf("failed to emit metric %q. Error: %v", m.Name, err)
+		}
+	}
+}
+
+func (e *AsyncEmitter) emit(m *Metric) {
+	m.Time = time.Now()
+	select {
+	case e.queue <- m:
+	default:
+		logrus.Errorf("metrics queue is full, dropping metric %q", m.Name)
+	}
+}
+
+// Incr implements the incr interface of metrics subsystem
+func (e *AsyncEmitter) Incr(name string) {
+	e.emit(&Metric{Name: name, Value: 1, Type: Counter})
+}
+
+// Gauge implements the gauge interface of metrics subsystem
+func (e *AsyncEmitter) Gauge(name string, value int64) {
+	e.emit(&Metric{Name: name, Value: float64(value), Type: Gauge})
+}
+
+// Timing implements the timing interface of metrics subsystem
+func (e *AsyncEmitter) Timing(name string, d time.Duration) {
+	e.emit(&Metric{Name: name, Value: float64(d) / float64(time.Millisecond), Type: Timer})
+}
+
+// Name returns the metric name formed by joining the parts with a dot. The parts are
+// lower cased and the characters that are special to statsd and graphite are replaced,
+// so that names can be formed from values like host-groups and node states.
+func Name(parts ...string) string {
+	names := make([]string, len(parts))
+	for i, p := range parts {
+		names[i] = strings.Map(func(r rune) rune {
+			switch {
+			case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+				return r
+			case r >= 'A' && r <= 'Z':
+				return r - 'A' + 'a'
+			}
+			return '_'
+		}, p)
+	}
+	return strings.Join(names, ".")
+}
+
+// prefixed returns the name with the prefix, if any
+func prefixed(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return strings.TrimSuffix(prefix, ".") + "." + name
+}
@@ -0,0 +1,103 @@
+// +build unittest
+
+package metrics
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type metricsSuite struct {
+}
+
+var _ = Suite(&metricsSuite{})
+
+// chanDriver sends the metrics on a channel
+type chanDriver chan *Metric
+
+func (d chanDriver) Send(m *Metric) error {
+	d <- m
+	return nil
+}
+
+func (s *metricsSuite) TestName(c *C) {
+	c.Assert(Name("nodes", "status", "Allocated"), Equals, "nodes.status.allocated")
+	c.Assert(Name("jobs", "set_config", "duration"), Equals, "jobs.set_config.duration")
+	c.Assert(Name("hosts", "service-master", "a.b:c|d"), Equals, "hosts.service-master.a_b_c_d")
+}
+
+func (s *metricsSuite) TestAsyncEmitter(c *C) {
+	d := make(chanDriver, 3)
+	e := NewAsyncEmitter(d)
+	e.Incr("jobs.commission.completed")
+	e.Gauge("events.queue_depth", 5)
+	e.Timing("jobs.commission.duration", 1500*time.Microsecond)
+
+	m := <-d
+	c.Assert(*m, DeepEquals, Metric{Name: "jobs.commission.completed", Value: 1, Type: Counter, Time: m.Time})
+	m = <-d
+	c.Assert(*m, DeepEquals, Metric{Name: "events.queue_depth", Value: 5, Type: Gauge, Time: m.Time})
+	m = <-d
+	c.Assert(*m, DeepEquals, Metric{Name: "jobs.commission.duration", Value: 1.5, Type: Timer, Time: m.Time})
+	c.Assert(m.Time.IsZero(), Equals, false)
+}
+
+func (s *metricsSuite) TestStatsDSend(c *C) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	defer pc.Close()
+
+	d := NewStatsDDriver(StatsDConfig{Addr: pc.LocalAddr().String()})
+	buf := make([]byte, 512)
+	for _, t := range []struct {
+		m     *Metric
+		exptd string
+	}{
+		{&Metric{Name: "jobs.commission.failed", Value: 1, Type: Counter}, "clusterm.jobs.commission.failed:1|c"},
+		{&Metric{Name: "nodes.total", Value: 3, Type: Gauge}, "clusterm.nodes.total:3|g"},
+		{&Metric{Name: "jobs.update.duration", Value: 12.5, Type: Timer}, "clusterm.jobs.update.duration:12.5|ms"},
+	} {
+		c.Assert(d.Send(t.m), IsNil)
+		c.Assert(pc.SetReadDeadline(time.Now().Add(5*time.Second)), IsNil)
+		n, _, err := pc.ReadFrom(buf)
+		c.Assert(err, IsNil)
+		c.Assert(string(buf[:n]), Equals, t.exptd)
+	}
+}
+
+func (s *metricsSuite) TestGraphiteSend(c *C) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	defer l.Close()
+	linesCh := make(chan string, 2)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			linesCh <- line
+		}
+	}()
+
+	d := NewGraphiteDriver(GraphiteConfig{Addr: l.Addr().String(), Prefix: "dc1.clusterm."})
+	tm := time.Unix(1451703845, 0)
+	c.Assert(d.Send(&Metric{Name: "nodes.state.discovered", Value: 2, Type: Gauge, Time: tm}), IsNil)
+	c.Assert(d.Send(&Metric{Name: "jobs.commission.duration", Value: 1200, Type: Timer, Time: tm}), IsNil)
+	c.Assert(<-linesCh, Equals, "dc1.clusterm.nodes.state.discovered 2 1451703845\n")
+	// the connection is reused
+	c.Assert(<-linesCh, Equals, "dc1.clusterm.jobs.commission.duration 1200 1451703845\n")
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/contiv/errored"
+)
+
+// StatsDConfig denotes the configuration for emitting metrics to statsd over udp
+type StatsDConfig struct {
+	// Addr is the address of statsd, like localhost:8125
+	Addr string `json:"addr"`
+	// Prefix is prepended to the metric names. It defaults to clusterm
+	Prefix string `json:"prefix"`
+}
+
+// StatsDDriver implements the metrics driver for statsd
+type StatsDDriver struct {
+	sync.Mutex
+	config StatsDConfig
+	conn   net.Conn
+}
+
+// NewStatsDDriver initializes and returns an instance of statsd driver
+func NewStatsDDriver(config StatsDConfig) *StatsDDriver {
+	if config.Prefix == "" {
+		config.Prefix = "clusterm"
+	}
+	return &StatsDDriver{
+		config: config,
+	}
+}
+
+// line returns the statsd line for the metric
+func (d *StatsDDriver) line(m *Metric) string {
+	return fmt.Sprintf("%s:%s|%s", prefixed(d.config.Prefix, m.Name),
+		strconv.FormatFloat(m.Value, 'f', -1, 64), m.Type)
+}
+
+// Send implements the send interface of metrics driver
+func (d *StatsDDriver) Send(m *Metric) error {
+	d.Lock()
+	defer d.Unlock()
+	if d.conn == nil {
+		conn, err := net.Dial("udp", d.config.Addr)
+		if err != nil {
+			return errored.Errorf("failed to connect to statsd at %q. Error: %v", d.config.Addr, err)
+		}
+		d.conn = conn
+	}
+	if _, err := d.conn.Write([]byte(d.line(m))); err != nil {
+		d.conn.Close()
+		d.conn = nil
+		return errored.Errorf("failed to send to statsd at %q. Error: %v", d.config.Addr, err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package monitor
+
+import (
+	"sync"
+
+	"github.com/contiv/errored"
+)
+
+// FakeNode describes a node in the fake monitoring subsystem
+type FakeNode struct {
+	Label  string `json:"label"`
+	Serial string `json:"serial"`
+	Addr   string `json:"addr"`
+}
+
+// FakeConfig describes the configuration for fake monitoring subsystem
+type FakeConfig struct {
+	// Nodes are the nodes that are discovered when the subsystem is started
+	Nodes []FakeNode `json:"nodes,omitempty"`
+}
+
+// FakeSubsys implements a monitoring sub-system that doesn't watch any real
+// nodes. The configured nodes are discovered on start and more nodes can be
+// made to join or fail later, which is useful to simulate a cluster.
+type FakeSubsys struct {
+	sync.Mutex
+	config        FakeConfig
+	discoveredCb  EventCb
+	disappearedCb EventCb
+	stopCh        chan struct{}
+}
+
+// NewFakeSubsys initializes and return a FakeSubsys instance
+func NewFakeSubsys(config FakeConfig) *FakeSubsys {
+	return &FakeSubsys{
+		config: config,
+		stopCh: make(chan struct{}),
+	}
+}
+
+// RegisterCb implements the callback registration interface of monitoring sub-system
+func (fm *FakeSubsys) RegisterCb(e EventType, cb EventCb) error {
+	fm.Lock()
+	defer fm.Unlock()
+	switch e {
+	case Discovered:
+		fm.discoveredCb = cb
+	case Disappeared:
+		fm.disappearedCb = cb
+	default:
+		return errored.Errorf("Unsupported event type: %d", e)
+	}
+	return nil
+}
+
+func fakeEvents(t EventType, nodes []FakeNode) []Event {
+	events := []Event{}
+	for _, n := range nodes {
+		events = append(events, Event{Type: t, Node: NewNode(n.Label, n.Serial, n.Addr)})
+	}
+	return events
+}
+
+// Join delivers the discovered event for the nodes
+func (fm *FakeSubsys) Join(nodes ...FakeNode) {
+	fm.Lock()
+	cb := fm.discoveredCb
+	fm.Unlock()
+	if cb != nil && len(nodes) > 0 {
+		cb(fakeEvents(Discovered, nodes))
+	}
+}
+
+// Fail delivers the disappeared event for the nodes
+func (fm *FakeSubsys) Fail(nodes ...FakeNode) {
+	fm.Lock()
+	cb := fm.disappearedCb
+	fm.Unlock()
+	if cb != nil && len(nodes) > 0 {
+		cb(fakeEvents(Disappeared, nodes))
+	}
+}
+
+// Start implements the start interface of monitoring sub-system. It discovers
+// the configured nodes and blocks until Stop is called.
+func (fm *FakeSubsys) Start() error {
+	fm.Join(fm.config.Nodes...)
+	<-fm.stopCh
+	return nil
+}
+
+// Stop unblocks the Start
+func (fm *FakeSubsys) Stop() {
+	close(fm.stopCh)
+}
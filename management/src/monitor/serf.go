@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/contiv/cluster/management/src/clustererr"
+	"github.com/contiv/cluster/management/src/retry"
 	"github.com/contiv/errored"
 	"github.com/mapuri/serf/client"
 	"github.com/mapuri/serfer"
@@ -20,17 +22,20 @@ const (
 // SerfSubsys implements monitoring sub-system for a serf based cluster
 type SerfSubsys struct {
 	config        *client.Config
+	retry         retry.Policy
 	router        *serfer.Router
 	discoveredCb  EventCb
 	disappearedCb EventCb
 }
 
-// NewSerfSubsys initializes and return a SerfSubsys instance
-func NewSerfSubsys(config *client.Config) *SerfSubsys {
+// NewSerfSubsys initializes and return a SerfSubsys instance. The monitor loop
+// is retried as per the policy on serf errors
+func NewSerfSubsys(config *client.Config, policy retry.Policy) *SerfSubsys {
 	//XXX: make a copy of the config as the serf client changes the config
 	c := *config
 	sm := &SerfSubsys{
 		config: &c,
+		retry:  policy,
 		router: serfer.NewRouter(),
 	}
 	return sm
@@ -120,16 +125,25 @@ func (sm *SerfSubsys) restore() error {
 	return nil
 }
 
-// Start implements the start interface of monitoring sub-system
+// Start implements the start interface of monitoring sub-system. It returns
+// only if the monitor loop fails after the attempts of the retry policy.
 func (sm *SerfSubsys) Start() error {
-	for {
+	r := retry.Retrier{
+		Policy: sm.retry,
+		OnRetry: func(attempt int, backoff time.Duration, err error) {
+			logrus.Infof("retrying monitor loop in %s, attempt %d. Last error: %v", backoff, attempt, err)
+		},
+	}
+	return r.Do(func() error {
 		if err := sm.restore(); err != nil {
 			logrus.Errorf("error occurred while restoring monitor state. Error: %v", err)
-		} else if err := sm.router.InitSerfFromConfigAndServe(sm.config); err != nil {
+			return clustererr.Wrap(clustererr.Monitor, "", err)
+		}
+		if err := sm.router.InitSerfFromConfigAndServe(sm.config); err != nil {
 			logrus.Errorf("error occurred in monitor loop. Error: %s", err)
+			return clustererr.Wrap(clustererr.Monitor, "", err)
 		}
-
 		// wait and retry for serf errors to be resolved
-		<-time.After(1 * time.Minute)
-	}
+		return clustererr.New(clustererr.Monitor, "monitor loop exited")
+	})
 }
@@ -0,0 +1,73 @@
+// Package netwriter implements a writer to a network server, like a syslog server
+// or graphite, over a connection that is reused across the writes and is
+// re-established on failure
+package netwriter
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/contiv/errored"
+)
+
+// timeout is the time allowed to connect to the server and for a write to it
+const timeout = 10 * time.Second
+
+// Writer writes to a server over a connection, that is dialed on the first
+// write. It is safe to be used concurrently.
+type Writer struct {
+	sync.Mutex
+	// name is the name of the server in the errors, like syslog server
+	name    string
+	network string
+	addr    string
+	conn    net.Conn
+}
+
+// New initializes and returns a writer to the server at the address. The name
+// denotes the server in the errors
+func New(name, network, addr string) *Writer {
+	return &Writer{
+		name:    name,
+		network: network,
+		addr:    addr,
+	}
+}
+
+// Write writes p to the server as a whole. A failed write is retried once on a
+// new connection, as the server may have closed the connection since the last
+// write.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.Lock()
+	defer w.Unlock()
+	for attempt := 0; attempt < 2; attempt++ {
+		if w.conn == nil {
+			conn, err := net.DialTimeout(w.network, w.addr, timeout)
+			if err != nil {
+				return 0, errored.Errorf("failed to connect to %s at %q. Error: %v", w.name, w.addr, err)
+			}
+			w.conn = conn
+		}
+		if err := w.conn.SetWriteDeadline(time.Now().Add(timeout)); err == nil {
+			if _, err = w.conn.Write(p); err == nil {
+				return len(p), nil
+			}
+		}
+		w.conn.Close()
+		w.conn = nil
+	}
+	return 0, errored.Errorf("failed to send to %s at %q", w.name, w.addr)
+}
+
+// Close closes the connection to the server, if any. A later write dials a new one
+func (w *Writer) Close() error {
+	w.Lock()
+	defer w.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
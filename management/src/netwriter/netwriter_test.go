@@ -0,0 +1,99 @@
+// +build unittest
+
+package netwriter
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type netwriterSuite struct {
+}
+
+var _ = Suite(&netwriterSuite{})
+
+// tcpServer returns a listener and a channel of the lines it receives, each
+// prefixed with the remote address of the connection it was received on
+func tcpServer(c *C) (net.Listener, chan string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	lines := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					lines <- conn.RemoteAddr().String() + " " + line
+				}
+			}(conn)
+		}
+	}()
+	return l, lines
+}
+
+func readLine(c *C, lines chan string) string {
+	select {
+	case line := <-lines:
+		return line
+	case <-time.After(5 * time.Second):
+		c.Fatal("timed out waiting for the line")
+	}
+	return ""
+}
+
+func (s *netwriterSuite) TestWriteReconnect(c *C) {
+	l, lines := tcpServer(c)
+	defer l.Close()
+
+	w := New("test server", "tcp", l.Addr().String())
+	n, err := w.Write([]byte("foo\n"))
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, 4)
+	first := readLine(c, lines)
+	c.Assert(first[len(first)-4:], Equals, "foo\n")
+
+	// the connection is reused
+	_, err = w.Write([]byte("bar\n"))
+	c.Assert(err, IsNil)
+	line := readLine(c, lines)
+	c.Assert(line, Equals, first[:len(first)-4]+"bar\n")
+
+	// a write on a failed connection is retried on a new one
+	w.conn.Close()
+	_, err = w.Write([]byte("baz\n"))
+	c.Assert(err, IsNil)
+	line = readLine(c, lines)
+	c.Assert(line[len(line)-4:], Equals, "baz\n")
+	c.Assert(line[:len(line)-4], Not(Equals), first[:len(first)-4])
+
+	c.Assert(w.Close(), IsNil)
+	c.Assert(w.Close(), IsNil)
+}
+
+func (s *netwriterSuite) TestWriteConnectFailure(c *C) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	addr := l.Addr().String()
+	l.Close()
+
+	w := New("test server", "tcp", addr)
+	n, err := w.Write([]byte("foo\n"))
+	c.Assert(err, ErrorMatches, `failed to connect to test server at "`+addr+`". Error: .*`)
+	c.Assert(n, Equals, 0)
+}
@@ -0,0 +1,151 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/smtp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/contiv/errored"
+)
+
+// EmailRecipients denotes a list of recipients that receive the events of specified
+// types and host-groups
+type EmailRecipients struct {
+	To []string `json:"to"`
+	// Events are the event types sent to the recipients. All events, except
+	// job_completed, are sent when empty
+	Events []EventType `json:"events,omitempty"`
+	// HostGroups are the host-groups whose node events are sent to the recipients.
+	// Node events from all host-groups are sent when empty.
+	HostGroups []string `json:"host_groups,omitempty"`
+}
+
+// EmailConfig denotes the configuration for email notifications, that are sent
+// through a smtp server
+type EmailConfig struct {
+	// SMTPAddr is the address of the smtp server, like smtp.example.com:587
+	SMTPAddr string `json:"smtp_addr"`
+	// User and Password are used for plain authentication with the smtp server, when set
+	User     string `json:"user,omitempty"`
+	Password string `json:"password,omitempty"`
+	From     string `json:"from"`
+	// To are the recipients of all the events routed to the channel
+	To []string `json:"to,omitempty"`
+	// Recipients are the recipients of the events of specific types and host-groups.
+	// These are routed irrespective of the channel's events and host-groups
+	Recipients    []EmailRecipients `json:"recipients,omitempty"`
+	SubjectPrefix string            `json:"subject_prefix,omitempty"`
+}
+
+// EmailNotifier implements the notifier for email
+type EmailNotifier struct {
+	config   EmailConfig
+	auth     smtp.Auth
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailNotifier initializes and returns an instance of email notifier
+func NewEmailNotifier(config EmailConfig) (*EmailNotifier, error) {
+	if config.From == "" {
+		return nil, errored.Errorf("from address is not specified")
+	}
+	host, _, err := net.SplitHostPort(config.SMTPAddr)
+	if err != nil {
+		return nil, errored.Errorf("invalid smtp server address %q. Error: %v", config.SMTPAddr, err)
+	}
+	if len(config.To) == 0 && len(config.Recipients) == 0 {
+		return nil, errored.Errorf("no recipients are specified")
+	}
+	if config.SubjectPrefix == "" {
+		config.SubjectPrefix = "[clusterm]"
+	}
+	n := &EmailNotifier{
+		config:   config,
+		sendMail: smtp.SendMail,
+	}
+	if config.User != "" {
+		n.auth = smtp.PlainAuth("", config.User, config.Password, host)
+	}
+	return n, nil
+}
+
+// recipients returns the de-duplicated list of recipients of the event. The To
+// addresses are included only if the event is routed to the channel
+func (n *EmailNotifier) recipients(e *Event, channelRoutes bool) []string {
+	seen := map[string]bool{}
+	to := []string{}
+	add := func(addrs []string) {
+		for _, addr := range addrs {
+			if !seen[addr] {
+				seen[addr] = true
+				to = append(to, addr)
+			}
+		}
+	}
+	if channelRoutes {
+		add(n.config.To)
+	}
+	for _, r := range n.config.Recipients {
+		if routes(r.Events, r.HostGroups, e) {
+			add(r.To)
+		}
+	}
+	return to
+}
+
+// headerValue strips the line breaks that can otherwise inject mail headers
+func headerValue(v string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(v)
+}
+
+// mailMessage returns the mail message, with headers, for the event
+func (n *EmailNotifier) mailMessage(e *Event, msg string, to []string) []byte {
+	subject := n.config.SubjectPrefix + " " + string(e.Type)
+	if e.Node != "" {
+		subject += ": " + e.Node
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", headerValue(n.config.From))
+	fmt.Fprintf(&b, "To: %s\r\n", headerValue(strings.Join(to, ", ")))
+	fmt.Fprintf(&b, "Subject: %s\r\n", headerValue(subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", e.Time.Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&b, "Content-Type: text/plain; charset=UTF-8\r\n")
+	fmt.Fprintf(&b, "\r\n%s\r\n", msg)
+
+	keys := []string{}
+	for k := range e.Details {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if len(keys) > 0 {
+		fmt.Fprintf(&b, "\r\n")
+	}
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s: %s\r\n", k, e.Details[k])
+	}
+	return b.Bytes()
+}
+
+// Send implements the send interface of notifier. The event is mailed to the
+// recipients it is routed to, if any.
+func (n *EmailNotifier) Send(e *Event, msg string) error {
+	return n.sendRouted(e, msg, true)
+}
+
+// sendRouted implements the recipientRouter interface
+func (n *EmailNotifier) sendRouted(e *Event, msg string, channelRoutes bool) error {
+	to := n.recipients(e, channelRoutes)
+	if len(to) == 0 {
+		return nil
+	}
+	if err := n.sendMail(n.config.SMTPAddr, n.auth, n.config.From, to, n.mailMessage(e, msg, to)); err != nil {
+		return errored.Errorf("failed to send mail through %q. Error: %v", n.config.SMTPAddr, err)
+	}
+	return nil
+}
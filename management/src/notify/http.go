@@ -0,0 +1,37 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/contiv/errored"
+)
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// postJSON posts the json encoded request to the url and returns error if the
+// response status is not one of 2xx
+func postJSON(url string, req interface{}) error {
+	var reqBody bytes.Buffer
+	if err := json.NewEncoder(&reqBody).Encode(req); err != nil {
+		return err
+	}
+
+	resp, err := httpClient.Post(url, "application/json", &reqBody)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			body = []byte{}
+		}
+		return errored.Errorf("status code %d unexpected. Response body: %q", resp.StatusCode, body)
+	}
+	return nil
+}
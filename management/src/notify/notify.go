@@ -0,0 +1,212 @@
+package notify
+
+import (
+	"bytes"
+	"text/template"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/contiv/errored"
+)
+
+// EventType enumerates the events that notifications are sent for
+type EventType string
+
+const (
+	// JobFailed event is sent when a job fails
+	JobFailed EventType = "job_failed"
+	// NodeDown event is sent when a commissioned node stays disappeared for longer than a threshold
+	NodeDown EventType = "node_down"
+	// QuorumRisk event is sent when a master node disappears and the count of masters that
+	// are up is such that quorum is lost or would be lost on losing one more master
+	QuorumRisk EventType = "quorum_risk"
+	// JobCompleted event is sent when a job completes successfully. Unlike the other
+	// events, it is only routed to the channels and recipients that list it explicitly
+	JobCompleted EventType = "job_completed"
+)
+
+// DefaultTemplates are the message templates used for event types that don't have
+// a template specified in the configuration
+var DefaultTemplates = map[EventType]string{
+	JobFailed:    "clusterm job failed. Job: {{.Job}}, Error: {{.Error}}",
+	JobCompleted: "clusterm job completed. Job: {{.Job}}",
+	NodeDown:     "node {{.Node}} in host-group {{.HostGroup}} has been down for more than {{.Details.down_for}}",
+	QuorumRisk: "{{.Details.masters_up}} of {{.Details.masters}} master nodes are up after node {{.Node}} went down. " +
+		`{{if eq .Details.quorum_lost "true"}}The quorum is lost.{{else}}The quorum shall be lost if one more master goes down.{{end}}`,
+}
+
+// Event denotes the occurrence that a notification is sent for
+type Event struct {
+	Type      EventType         `json:"type"`
+	Time      time.Time         `json:"time"`
+	Node      string            `json:"node,omitempty"`
+	HostGroup string            `json:"host_group,omitempty"`
+	Job       string            `json:"job,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+}
+
+// Subsys provides the following services to the cluster manager:
+// - Interface to notify the events to the configured channels.
+type Subsys interface {
+	// Notify sends the event to the channels it is routed to. It doesn't block
+	// on sending the notification and the failures are only logged.
+	Notify(e *Event)
+}
+
+// Notifier is implemented by the drivers that deliver the notification messages
+type Notifier interface {
+	// Send delivers the message formed for the event
+	Send(e *Event, msg string) error
+}
+
+// ChannelConfig denotes the configuration of a notification channel
+type ChannelConfig struct {
+	Name      string           `json:"name"`
+	Slack     *SlackConfig     `json:"slack,omitempty"`
+	PagerDuty *PagerDutyConfig `json:"pagerduty,omitempty"`
+	Email     *EmailConfig     `json:"email,omitempty"`
+	// Events are the event types routed to the channel. All events, except job_completed,
+	// are routed when empty
+	Events []EventType `json:"events,omitempty"`
+	// HostGroups are the host-groups whose node events are routed to the channel. Node
+	// events from all host-groups are routed when empty. The events that are not
+	// associated with a host-group are routed irrespective of this.
+	HostGroups []string `json:"host_groups,omitempty"`
+}
+
+// routes returns true if the event shall be routed to the channel
+func (c *ChannelConfig) routes(e *Event) bool {
+	return routes(c.Events, c.HostGroups, e)
+}
+
+// routes returns true if the event matches the event types and host-groups
+func routes(events []EventType, hostGroups []string, e *Event) bool {
+	if len(events) == 0 && e.Type == JobCompleted {
+		return false
+	}
+	if len(events) > 0 && !contains(events, e.Type) {
+		return false
+	}
+	if len(hostGroups) > 0 && e.HostGroup != "" {
+		for _, group := range hostGroups {
+			if group == e.HostGroup {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func contains(types []EventType, t EventType) bool {
+	for _, et := range types {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+// recipientRouter is implemented by the notifiers that route the events to their
+// own recipients, like the email recipients lists. These are sent the events that
+// are not routed to the channel, as a recipient's routing is independent of the
+// channel's.
+type recipientRouter interface {
+	// sendRouted delivers the message to the recipients the event is routed to.
+	// channelRoutes is true if the event is routed to the channel
+	sendRouted(e *Event, msg string, channelRoutes bool) error
+}
+
+type channel struct {
+	config   ChannelConfig
+	notifier Notifier
+}
+
+// Dispatcher implements the notification subsystem by routing the events to the channels
+type Dispatcher struct {
+	channels  []*channel
+	templates map[EventType]*template.Template
+}
+
+// NewDispatcher initializes and returns an instance of notification subsystem. The
+// templates override the DefaultTemplates for the respective event types.
+func NewDispatcher(channels []ChannelConfig, templates map[EventType]string) (*Dispatcher, error) {
+	d := &Dispatcher{
+		templates: make(map[EventType]*template.Template),
+	}
+	for t, tmpl := range DefaultTemplates {
+		if override, ok := templates[t]; ok {
+			tmpl = override
+		}
+		var err error
+		if d.templates[t], err = template.New(string(t)).Option("missingkey=zero").Parse(tmpl); err != nil {
+			return nil, errored.Errorf("failed to parse notification template for %q. Error: %v", t, err)
+		}
+	}
+	for t := range templates {
+		if _, ok := DefaultTemplates[t]; !ok {
+			return nil, errored.Errorf("notification template specified for unknown event %q", t)
+		}
+	}
+
+	for _, c := range channels {
+		ch := &channel{config: c}
+		// We give priority to slack, followed by pagerduty, if more than one is set for a channel
+		if c.Slack != nil {
+			ch.notifier = NewSlackNotifier(*c.Slack)
+		} else if c.PagerDuty != nil {
+			ch.notifier = NewPagerDutyNotifier(*c.PagerDuty)
+		} else if c.Email != nil {
+			var err error
+			if ch.notifier, err = NewEmailNotifier(*c.Email); err != nil {
+				return nil, errored.Errorf("invalid email config for channel %q. Error: %v", c.Name, err)
+			}
+		} else {
+			return nil, errored.Errorf("no notification driver configured for channel %q", c.Name)
+		}
+		d.channels = append(d.channels, ch)
+	}
+	return d, nil
+}
+
+// message forms the notification message for the event
+func (d *Dispatcher) message(e *Event) (string, error) {
+	tmpl, ok := d.templates[e.Type]
+	if !ok {
+		return "", errored.Errorf("no notification template for event %q", e.Type)
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, e); err != nil {
+		return "", errored.Errorf("failed to form notification message for event %q. Error: %v", e.Type, err)
+	}
+	return out.String(), nil
+}
+
+// dispatch sends the event to the channels it is routed to
+func (d *Dispatcher) dispatch(e *Event) {
+	msg, err := d.message(e)
+	if err != nil {
+		logrus.Errorf("%v", err)
+		return
+	}
+	for _, ch := range d.channels {
+		var err error
+		if rr, ok := ch.notifier.(recipientRouter); ok {
+			err = rr.sendRouted(e, msg, ch.config.routes(e))
+		} else if ch.config.routes(e) {
+			err = ch.notifier.Send(e, msg)
+		}
+		if err != nil {
+			logrus.Errorf("failed to send %q notification to channel %q. Error: %v", e.Type, ch.config.Name, err)
+		}
+	}
+}
+
+// Notify implements the notify interface of notification subsystem
+func (d *Dispatcher) Notify(e *Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	go d.dispatch(e)
+}
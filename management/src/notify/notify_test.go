@@ -0,0 +1,276 @@
+// +build unittest
+
+package notify
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type notifySuite struct {
+}
+
+var _ = Suite(&notifySuite{})
+
+// fakeEndpoint records the json requests posted to it
+func fakeEndpoint(c *C) (*httptest.Server, *[]map[string]interface{}) {
+	reqs := &[]map[string]interface{}{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		*reqs = append(*reqs, req)
+	}))
+	return ts, reqs
+}
+
+func (s *notifySuite) TestDefaultTemplates(c *C) {
+	d, err := NewDispatcher(nil, nil)
+	c.Assert(err, IsNil)
+
+	msg, err := d.message(&Event{Type: JobFailed, Job: "commissionEvent", Error: "failed"})
+	c.Assert(err, IsNil)
+	c.Assert(msg, Equals, "clusterm job failed. Job: commissionEvent, Error: failed")
+
+	msg, err = d.message(&Event{Type: NodeDown, Node: "node1", HostGroup: "service-worker",
+		Details: map[string]string{"down_for": "5m0s"}})
+	c.Assert(err, IsNil)
+	c.Assert(msg, Equals, "node node1 in host-group service-worker has been down for more than 5m0s")
+
+	msg, err = d.message(&Event{Type: QuorumRisk, Node: "node1",
+		Details: map[string]string{"masters": "3", "masters_up": "1", "quorum_lost": "true"}})
+	c.Assert(err, IsNil)
+	c.Assert(msg, Equals, "1 of 3 master nodes are up after node node1 went down. The quorum is lost.")
+}
+
+func (s *notifySuite) TestTemplateOverride(c *C) {
+	d, err := NewDispatcher(nil, map[EventType]string{JobFailed: "failed: {{.Job}}"})
+	c.Assert(err, IsNil)
+	msg, err := d.message(&Event{Type: JobFailed, Job: "decommissionEvent"})
+	c.Assert(err, IsNil)
+	c.Assert(msg, Equals, "failed: decommissionEvent")
+
+	_, err = NewDispatcher(nil, map[EventType]string{JobFailed: "{{.Job"})
+	c.Assert(err, NotNil)
+	_, err = NewDispatcher(nil, map[EventType]string{"foo": "{{.Job}}"})
+	c.Assert(err, NotNil)
+}
+
+func (s *notifySuite) TestChannelWithoutDriver(c *C) {
+	_, err := NewDispatcher([]ChannelConfig{{Name: "ops"}}, nil)
+	c.Assert(err, NotNil)
+}
+
+func (s *notifySuite) TestRouting(c *C) {
+	ch := &ChannelConfig{Events: []EventType{NodeDown}, HostGroups: []string{"service-master"}}
+	c.Assert(ch.routes(&Event{Type: NodeDown, HostGroup: "service-master"}), Equals, true)
+	c.Assert(ch.routes(&Event{Type: NodeDown, HostGroup: "service-worker"}), Equals, false)
+	c.Assert(ch.routes(&Event{Type: JobFailed}), Equals, false)
+
+	ch = &ChannelConfig{HostGroups: []string{"service-master"}}
+	c.Assert(ch.routes(&Event{Type: JobFailed}), Equals, true)
+	c.Assert(ch.routes(&Event{Type: QuorumRisk, HostGroup: "service-master"}), Equals, true)
+	// job completion is only routed when listed explicitly
+	c.Assert(ch.routes(&Event{Type: JobCompleted}), Equals, false)
+
+	ch = &ChannelConfig{Events: []EventType{JobCompleted}}
+	c.Assert(ch.routes(&Event{Type: JobCompleted}), Equals, true)
+}
+
+func (s *notifySuite) TestDispatchSlackAndPagerDuty(c *C) {
+	slack, slackReqs := fakeEndpoint(c)
+	defer slack.Close()
+	pd, pdReqs := fakeEndpoint(c)
+	defer pd.Close()
+
+	d, err := NewDispatcher([]ChannelConfig{
+		{
+			Name:  "chat",
+			Slack: &SlackConfig{WebhookURL: slack.URL, Channel: "#ops"},
+		},
+		{
+			Name:      "oncall",
+			PagerDuty: &PagerDutyConfig{RoutingKey: "key", EventsURL: pd.URL},
+			Events:    []EventType{QuorumRisk},
+		},
+	}, nil)
+	c.Assert(err, IsNil)
+
+	d.dispatch(&Event{Type: JobFailed, Job: "commissionEvent", Error: "failed"})
+	d.dispatch(&Event{Type: QuorumRisk, Node: "node1", Time: time.Now(),
+		Details: map[string]string{"masters": "3", "masters_up": "2"}})
+
+	c.Assert(*slackReqs, HasLen, 2)
+	c.Assert((*slackReqs)[0]["text"], Equals, "clusterm job failed. Job: commissionEvent, Error: failed")
+	c.Assert((*slackReqs)[0]["channel"], Equals, "#ops")
+	c.Assert((*slackReqs)[0]["username"], Equals, "clusterm")
+
+	c.Assert(*pdReqs, HasLen, 1)
+	c.Assert((*pdReqs)[0]["routing_key"], Equals, "key")
+	c.Assert((*pdReqs)[0]["event_action"], Equals, "trigger")
+	c.Assert((*pdReqs)[0]["dedup_key"], Equals, "clusterm-quorum_risk-node1")
+	payload := (*pdReqs)[0]["payload"].(map[string]interface{})
+	c.Assert(payload["severity"], Equals, "critical")
+	c.Assert(payload["source"], Equals, "node1")
+	c.Assert(payload["summary"], Equals,
+		"2 of 3 master nodes are up after node node1 went down. The quorum shall be lost if one more master goes down.")
+}
+
+// fakeSMTP serves a single smtp session and returns the envelope recipients and
+// the data of the received mail on mailCh
+func fakeSMTP(c *C) (string, chan []string, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	mailCh := make(chan []string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		fmt.Fprintf(conn, "220 localhost ESMTP\r\n")
+		rcpts := []string{}
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				fmt.Fprintf(conn, "250 localhost\r\n")
+			case strings.HasPrefix(cmd, "RCPT TO:"):
+				rcpts = append(rcpts, strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>"))
+				fmt.Fprintf(conn, "250 OK\r\n")
+			case cmd == "DATA":
+				fmt.Fprintf(conn, "354 go ahead\r\n")
+				data := ""
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil || l == ".\r\n" {
+						break
+					}
+					data += l
+				}
+				mailCh <- append(rcpts, data)
+				fmt.Fprintf(conn, "250 OK\r\n")
+			case cmd == "QUIT":
+				fmt.Fprintf(conn, "221 bye\r\n")
+				return
+			default:
+				fmt.Fprintf(conn, "250 OK\r\n")
+			}
+		}
+	}()
+	return l.Addr().String(), mailCh, func() { l.Close() }
+}
+
+func (s *notifySuite) TestEmailConfig(c *C) {
+	_, err := NewEmailNotifier(EmailConfig{SMTPAddr: "localhost:25", To: []string{"ops@example.com"}})
+	c.Assert(err, ErrorMatches, ".*from address is not specified.*")
+	_, err = NewEmailNotifier(EmailConfig{SMTPAddr: "localhost", From: "clusterm@example.com",
+		To: []string{"ops@example.com"}})
+	c.Assert(err, ErrorMatches, ".*invalid smtp server address.*")
+	_, err = NewEmailNotifier(EmailConfig{SMTPAddr: "localhost:25", From: "clusterm@example.com"})
+	c.Assert(err, ErrorMatches, ".*no recipients are specified.*")
+	_, err = NewDispatcher([]ChannelConfig{{Name: "mail", Email: &EmailConfig{SMTPAddr: "localhost:25"}}}, nil)
+	c.Assert(err, ErrorMatches, ".*invalid email config for channel \"mail\".*")
+}
+
+func (s *notifySuite) TestEmailRecipients(c *C) {
+	n, err := NewEmailNotifier(EmailConfig{
+		SMTPAddr: "localhost:25",
+		From:     "clusterm@example.com",
+		To:       []string{"ops@example.com"},
+		Recipients: []EmailRecipients{
+			{To: []string{"masters@example.com", "ops@example.com"}, HostGroups: []string{"service-master"}},
+			{To: []string{"jobs@example.com"}, Events: []EventType{JobFailed, JobCompleted}},
+		},
+	})
+	c.Assert(err, IsNil)
+
+	c.Assert(n.recipients(&Event{Type: NodeDown, HostGroup: "service-master"}, true), DeepEquals,
+		[]string{"ops@example.com", "masters@example.com"})
+	c.Assert(n.recipients(&Event{Type: NodeDown, HostGroup: "service-worker"}, true), DeepEquals,
+		[]string{"ops@example.com"})
+	c.Assert(n.recipients(&Event{Type: JobCompleted, HostGroup: "service-master"}, true), DeepEquals,
+		[]string{"ops@example.com", "jobs@example.com"})
+	c.Assert(n.recipients(&Event{Type: JobFailed}, true), DeepEquals,
+		[]string{"ops@example.com", "masters@example.com", "jobs@example.com"})
+	// the recipients lists are routed independent of the channel
+	c.Assert(n.recipients(&Event{Type: JobCompleted}, false), DeepEquals, []string{"jobs@example.com"})
+}
+
+func (s *notifySuite) TestDispatchEmail(c *C) {
+	addr, mailCh, stop := fakeSMTP(c)
+	defer stop()
+
+	d, err := NewDispatcher([]ChannelConfig{
+		{
+			Name: "mail",
+			Email: &EmailConfig{
+				SMTPAddr: addr,
+				From:     "clusterm@example.com",
+				Recipients: []EmailRecipients{
+					{To: []string{"workers@example.com"}, HostGroups: []string{"service-worker"}},
+				},
+			},
+		},
+	}, nil)
+	c.Assert(err, IsNil)
+
+	// not mailed as there are no recipients for the host-group
+	d.dispatch(&Event{Type: NodeDown, Node: "node2", HostGroup: "service-master"})
+
+	d.dispatch(&Event{Type: NodeDown, Node: "node1", HostGroup: "service-worker", Time: time.Now(),
+		Details: map[string]string{"down_for": "5m0s"}})
+	mail := <-mailCh
+	c.Assert(mail[0], Equals, "workers@example.com")
+	c.Assert(mail[1], Matches, "(?s)From: clusterm@example.com\r\nTo: workers@example.com\r\n"+
+		"Subject: \\[clusterm\\] node_down: node1\r\n.*\r\n\r\n"+
+		"node node1 in host-group service-worker has been down for more than 5m0s\r\n\r\ndown_for: 5m0s\r\n")
+}
+
+func (s *notifySuite) TestDispatchEmailRecipientEvents(c *C) {
+	addr, mailCh, stop := fakeSMTP(c)
+	defer stop()
+
+	// job_completed is not routed to the channel as it doesn't list it, but the
+	// recipients that list it are mailed
+	d, err := NewDispatcher([]ChannelConfig{
+		{
+			Name:       "mail",
+			HostGroups: []string{"service-master"},
+			Email: &EmailConfig{
+				SMTPAddr: addr,
+				From:     "clusterm@example.com",
+				To:       []string{"ops@example.com"},
+				Recipients: []EmailRecipients{
+					{To: []string{"storage-team@example.com"}, Events: []EventType{NodeDown, JobCompleted},
+						HostGroups: []string{"service-worker"}},
+				},
+			},
+		},
+	}, nil)
+	c.Assert(err, IsNil)
+
+	d.dispatch(&Event{Type: JobCompleted, Job: "commissionEvent", Time: time.Now()})
+	mail := <-mailCh
+	c.Assert(mail[:len(mail)-1], DeepEquals, []string{"storage-team@example.com"})
+	c.Assert(mail[len(mail)-1], Matches, "(?s).*clusterm job completed. Job: commissionEvent.*")
+}
@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"time"
+)
+
+// PagerDutyConfig denotes the configuration for pagerduty notifications, that are
+// triggered as alerts through the events api (v2)
+type PagerDutyConfig struct {
+	RoutingKey string `json:"routing_key"`
+	// EventsURL is the url of the events api. It needs to be changed only for testing
+	EventsURL string `json:"events_url,omitempty"`
+	// Severities overrides the default alert severity for the event types
+	Severities map[EventType]string `json:"severities,omitempty"`
+}
+
+// defaultSeverities is the alert severity of the event types, as per pagerduty's
+// severity levels viz. critical, error, warning and info
+var defaultSeverities = map[EventType]string{
+	JobFailed:    "error",
+	NodeDown:     "error",
+	QuorumRisk:   "critical",
+	JobCompleted: "info",
+}
+
+// PagerDutyNotifier implements the notifier for pagerduty
+type PagerDutyNotifier struct {
+	config PagerDutyConfig
+}
+
+// NewPagerDutyNotifier initializes and returns an instance of pagerduty notifier
+func NewPagerDutyNotifier(config PagerDutyConfig) *PagerDutyNotifier {
+	if config.EventsURL == "" {
+		config.EventsURL = "https://events.pagerduty.com/v2/enqueue"
+	}
+	return &PagerDutyNotifier{
+		config: config,
+	}
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string `json:"routing_key"`
+	EventAction string `json:"event_action"`
+	DedupKey    string `json:"dedup_key,omitempty"`
+	Payload     struct {
+		Summary       string `json:"summary"`
+		Source        string `json:"source"`
+		Severity      string `json:"severity"`
+		Timestamp     string `json:"timestamp"`
+		Class         string `json:"class"`
+		CustomDetails *Event `json:"custom_details"`
+	} `json:"payload"`
+}
+
+func (n *PagerDutyNotifier) severity(t EventType) string {
+	if s, ok := n.config.Severities[t]; ok {
+		return s
+	}
+	if s, ok := defaultSeverities[t]; ok {
+		return s
+	}
+	return "error"
+}
+
+// Send implements the send interface of notifier
+func (n *PagerDutyNotifier) Send(e *Event, msg string) error {
+	pe := &pagerDutyEvent{
+		RoutingKey:  n.config.RoutingKey,
+		EventAction: "trigger",
+	}
+	// repeated events for a node are de-duplicated into one alert
+	if e.Node != "" {
+		pe.DedupKey = "clusterm-" + string(e.Type) + "-" + e.Node
+	}
+	pe.Payload.Summary = msg
+	pe.Payload.Source = "clusterm"
+	if e.Node != "" {
+		pe.Payload.Source = e.Node
+	}
+	pe.Payload.Severity = n.severity(e.Type)
+	pe.Payload.Timestamp = e.Time.Format(time.RFC3339)
+	pe.Payload.Class = string(e.Type)
+	pe.Payload.CustomDetails = e
+	return postJSON(n.config.EventsURL, pe)
+}
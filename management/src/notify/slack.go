@@ -0,0 +1,40 @@
+package notify
+
+// SlackConfig denotes the configuration for slack notifications, that are posted
+// through an incoming webhook
+type SlackConfig struct {
+	WebhookURL string `json:"webhook_url"`
+	// Channel overrides the default channel of the webhook, when set
+	Channel  string `json:"channel,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+// SlackNotifier implements the notifier for slack
+type SlackNotifier struct {
+	config SlackConfig
+}
+
+// NewSlackNotifier initializes and returns an instance of slack notifier
+func NewSlackNotifier(config SlackConfig) *SlackNotifier {
+	if config.Username == "" {
+		config.Username = "clusterm"
+	}
+	return &SlackNotifier{
+		config: config,
+	}
+}
+
+type slackMessage struct {
+	Text     string `json:"text"`
+	Channel  string `json:"channel,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+// Send implements the send interface of notifier
+func (n *SlackNotifier) Send(e *Event, msg string) error {
+	return postJSON(n.config.WebhookURL, &slackMessage{
+		Text:     msg,
+		Channel:  n.config.Channel,
+		Username: n.config.Username,
+	})
+}
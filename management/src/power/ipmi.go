@@ -0,0 +1,73 @@
+package power
+
+import (
+	"os"
+	"os/exec"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/contiv/errored"
+)
+
+// IPMIConfig denotes the configuration for ipmitool based power subsystem
+type IPMIConfig struct {
+	Interface string `json:"interface"`
+}
+
+// DefaultIPMIConfig returns the default configuration values for the ipmi power subsystem
+func DefaultIPMIConfig() IPMIConfig {
+	return IPMIConfig{
+		Interface: "lanplus",
+	}
+}
+
+// IPMISubsys implements the power subsystem using ipmitool
+type IPMISubsys struct {
+	config IPMIConfig
+}
+
+// NewIPMISubsys initializes and returns an instance of ipmitool based power subsystem
+func NewIPMISubsys(config IPMIConfig) *IPMISubsys {
+	if config.Interface == "" {
+		config.Interface = DefaultIPMIConfig().Interface
+	}
+	return &IPMISubsys{
+		config: config,
+	}
+}
+
+// ipmiArgs returns the ipmitool arguments for issuing the specified chassis power command.
+// The password is passed through the environment so it doesn't show up in process list.
+func (s *IPMISubsys) ipmiArgs(bmc *BMC, cmd string) []string {
+	return []string{"-I", s.config.Interface, "-H", bmc.Addr, "-U", bmc.User, "-E",
+		"chassis", "power", cmd}
+}
+
+func (s *IPMISubsys) chassisPower(bmc *BMC, cmd string) error {
+	if err := bmc.Validate(); err != nil {
+		return err
+	}
+	c := exec.Command("ipmitool", s.ipmiArgs(bmc, cmd)...)
+	c.Env = append(os.Environ(), "IPMI_PASSWORD="+bmc.Password)
+	output, err := c.CombinedOutput()
+	if err != nil {
+		return errored.Errorf("ipmitool chassis power %s failed for %s. Output: %s, Error: %v",
+			cmd, bmc, output, err)
+	}
+	logrus.Debugf("ipmitool chassis power %s for %s. Output: %s", cmd, bmc, output)
+	return nil
+}
+
+// PowerOn implements the power on interface of power subsystem
+func (s *IPMISubsys) PowerOn(bmc *BMC) error {
+	return s.chassisPower(bmc, "on")
+}
+
+// PowerOff implements the power off interface of power subsystem
+func (s *IPMISubsys) PowerOff(bmc *BMC) error {
+	return s.chassisPower(bmc, "off")
+}
+
+// PowerCycle implements the power cycle interface of power subsystem
+func (s *IPMISubsys) PowerCycle(bmc *BMC) error {
+	return s.chassisPower(bmc, "cycle")
+}
@@ -0,0 +1,36 @@
+package power
+
+import "github.com/contiv/errored"
+
+// BMC denotes the information needed to access the baseboard management
+// controller (BMC) of a node
+type BMC struct {
+	Addr     string
+	User     string
+	Password string
+}
+
+// String returns the description of the BMC. It intentionally leaves out the password.
+func (b *BMC) String() string {
+	return b.User + "@" + b.Addr
+}
+
+// Validate checks that the BMC info is complete
+func (b *BMC) Validate() error {
+	if b.Addr == "" || b.User == "" {
+		return errored.Errorf("BMC address and user need to be specified. BMC: %s", b)
+	}
+	return nil
+}
+
+// Subsys provides the following services to the cluster manager:
+// - Interface to control the power of a node through it's BMC.
+type Subsys interface {
+	// PowerOn powers on the node
+	PowerOn(bmc *BMC) error
+	// PowerOff powers off the node. The node is powered off immediately,
+	// it doesn't wait for the node's OS to shutdown gracefully.
+	PowerOff(bmc *BMC) error
+	// PowerCycle powers off and then powers on the node
+	PowerCycle(bmc *BMC) error
+}
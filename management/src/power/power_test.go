@@ -0,0 +1,112 @@
+// +build unittest
+
+package power
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type powerSuite struct {
+}
+
+var _ = Suite(&powerSuite{})
+
+func (s *powerSuite) TestBMCValidate(c *C) {
+	c.Assert((&BMC{Addr: "1.2.3.4", User: "admin"}).Validate(), IsNil)
+	c.Assert((&BMC{Addr: "1.2.3.4"}).Validate(), NotNil)
+	c.Assert((&BMC{User: "admin"}).Validate(), NotNil)
+}
+
+func (s *powerSuite) TestBMCStringNoPassword(c *C) {
+	bmc := &BMC{Addr: "1.2.3.4", User: "admin", Password: "secret"}
+	c.Assert(strings.Contains(bmc.String(), bmc.Password), Equals, false)
+}
+
+func (s *powerSuite) TestIPMIArgs(c *C) {
+	subsys := NewIPMISubsys(IPMIConfig{})
+	bmc := &BMC{Addr: "1.2.3.4", User: "admin", Password: "secret"}
+	args := subsys.ipmiArgs(bmc, "cycle")
+	c.Assert(args, DeepEquals, []string{"-I", "lanplus", "-H", "1.2.3.4", "-U", "admin", "-E",
+		"chassis", "power", "cycle"})
+}
+
+func getRedfishTestSubsysAndServer(handler http.HandlerFunc) (*httptest.Server, *RedfishSubsys, *BMC) {
+	srvr := httptest.NewTLSServer(handler)
+	subsys := NewRedfishSubsys(RedfishConfig{})
+	subsys.client = &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	bmc := &BMC{
+		Addr:     strings.TrimPrefix(srvr.URL, "https://"),
+		User:     "admin",
+		Password: "secret",
+	}
+	return srvr, subsys, bmc
+}
+
+func (s *powerSuite) TestRedfishReset(c *C) {
+	tests := map[string]struct {
+		cb            func(subsys *RedfishSubsys) func(bmc *BMC) error
+		exptdResetTyp string
+	}{
+		"power-on": {
+			cb:            func(subsys *RedfishSubsys) func(bmc *BMC) error { return subsys.PowerOn },
+			exptdResetTyp: redfishResetOn,
+		},
+		"power-off": {
+			cb:            func(subsys *RedfishSubsys) func(bmc *BMC) error { return subsys.PowerOff },
+			exptdResetTyp: redfishResetOff,
+		},
+		"power-cycle": {
+			cb:            func(subsys *RedfishSubsys) func(bmc *BMC) error { return subsys.PowerCycle },
+			exptdResetTyp: redfishResetCycle,
+		},
+	}
+
+	for testname, test := range tests {
+		srvr, subsys, bmc := getRedfishTestSubsysAndServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				user, password, ok := r.BasicAuth()
+				c.Assert(ok, Equals, true, Commentf("test: %s", testname))
+				c.Assert(user, Equals, "admin", Commentf("test: %s", testname))
+				c.Assert(password, Equals, "secret", Commentf("test: %s", testname))
+				c.Assert(r.URL.Path, Equals, "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset",
+					Commentf("test: %s", testname))
+				req := struct {
+					ResetType string `json:"ResetType"`
+				}{}
+				c.Assert(json.NewDecoder(r.Body).Decode(&req), IsNil, Commentf("test: %s", testname))
+				c.Assert(req.ResetType, Equals, test.exptdResetTyp, Commentf("test: %s", testname))
+				w.WriteHeader(http.StatusNoContent)
+			}))
+		c.Assert(test.cb(subsys)(bmc), IsNil, Commentf("test: %s", testname))
+		srvr.Close()
+	}
+}
+
+func (s *powerSuite) TestRedfishResetFailure(c *C) {
+	srvr, subsys, bmc := getRedfishTestSubsysAndServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "test failure", http.StatusInternalServerError)
+		}))
+	defer srvr.Close()
+	err := subsys.PowerOn(bmc)
+	c.Assert(err, ErrorMatches, "(?s)redfish reset \"On\" failed.*test failure.*")
+}
+
+func (s *powerSuite) TestRedfishResetInvalidBMC(c *C) {
+	subsys := NewRedfishSubsys(DefaultRedfishConfig())
+	c.Assert(subsys.PowerOff(&BMC{}), NotNil)
+}
@@ -0,0 +1,109 @@
+package power
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/contiv/errored"
+)
+
+// RedfishConfig denotes the configuration for redfish based power subsystem
+type RedfishConfig struct {
+	// SystemPath is the path of the computer system resource on the BMC
+	SystemPath string `json:"system_path"`
+	// InsecureSkipVerify allows talking to BMCs with self-signed certificates
+	InsecureSkipVerify bool `json:"insecure_skip_verify"`
+}
+
+// DefaultRedfishConfig returns the default configuration values for the redfish power subsystem
+func DefaultRedfishConfig() RedfishConfig {
+	return RedfishConfig{
+		SystemPath: "/redfish/v1/Systems/1",
+	}
+}
+
+const (
+	redfishResetOn    = "On"
+	redfishResetOff   = "ForceOff"
+	redfishResetCycle = "PowerCycle"
+)
+
+// RedfishSubsys implements the power subsystem using the redfish REST api
+type RedfishSubsys struct {
+	config RedfishConfig
+	client *http.Client
+}
+
+// NewRedfishSubsys initializes and returns an instance of redfish based power subsystem
+func NewRedfishSubsys(config RedfishConfig) *RedfishSubsys {
+	if config.SystemPath == "" {
+		config.SystemPath = DefaultRedfishConfig().SystemPath
+	}
+	return &RedfishSubsys{
+		config: config,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: config.InsecureSkipVerify},
+			},
+		},
+	}
+}
+
+func (s *RedfishSubsys) reset(bmc *BMC, resetType string) error {
+	if err := bmc.Validate(); err != nil {
+		return err
+	}
+
+	var reqBody bytes.Buffer
+	if err := json.NewEncoder(&reqBody).Encode(struct {
+		ResetType string `json:"ResetType"`
+	}{ResetType: resetType}); err != nil {
+		return err
+	}
+
+	reqURL := "https://" + bmc.Addr + s.config.SystemPath + "/Actions/ComputerSystem.Reset"
+	req, err := http.NewRequest("POST", reqURL, &reqBody)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(bmc.User, bmc.Password)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted &&
+		resp.StatusCode != http.StatusNoContent {
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			body = []byte{}
+		}
+		return errored.Errorf("redfish reset %q failed for %s. Status code %d unexpected. Response body: %q",
+			resetType, bmc, resp.StatusCode, body)
+	}
+
+	return nil
+}
+
+// PowerOn implements the power on interface of power subsystem
+func (s *RedfishSubsys) PowerOn(bmc *BMC) error {
+	return s.reset(bmc, redfishResetOn)
+}
+
+// PowerOff implements the power off interface of power subsystem
+func (s *RedfishSubsys) PowerOff(bmc *BMC) error {
+	return s.reset(bmc, redfishResetOff)
+}
+
+// PowerCycle implements the power cycle interface of power subsystem
+func (s *RedfishSubsys) PowerCycle(bmc *BMC) error {
+	return s.reset(bmc, redfishResetCycle)
+}
@@ -0,0 +1,77 @@
+package publisher
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/contiv/errored"
+)
+
+// KafkaConfig denotes the configuration for publishing events to kafka. The events
+// are published through a kafka REST proxy.
+type KafkaConfig struct {
+	// RESTProxyURL is the url of the kafka REST proxy, like http://localhost:8082
+	RESTProxyURL string `json:"rest_proxy_url"`
+	Topic        string `json:"topic"`
+}
+
+// KafkaDriver implements the publisher driver for kafka
+type KafkaDriver struct {
+	config KafkaConfig
+	client *http.Client
+}
+
+// NewKafkaDriver initializes and returns an instance of kafka publisher driver
+func NewKafkaDriver(config KafkaConfig) *KafkaDriver {
+	if config.Topic == "" {
+		config.Topic = "clusterm-events"
+	}
+	return &KafkaDriver{
+		config: config,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type kafkaRecords struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaRecord struct {
+	Key   string `json:"key"`
+	Value *Event `json:"value"`
+}
+
+// Send implements the send interface of publisher driver
+func (d *KafkaDriver) Send(e *Event) error {
+	var reqBody bytes.Buffer
+	if err := json.NewEncoder(&reqBody).Encode(&kafkaRecords{
+		Records: []kafkaRecord{{Key: e.key(), Value: e}},
+	}); err != nil {
+		return err
+	}
+
+	url := d.config.RESTProxyURL + "/topics/" + d.config.Topic
+	req, err := http.NewRequest("POST", url, &reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			body = []byte{}
+		}
+		return errored.Errorf("publishing to kafka topic %q failed. Status code %d unexpected. Response body: %q",
+			d.config.Topic, resp.StatusCode, body)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package publisher
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/contiv/errored"
+)
+
+// NATSConfig denotes the configuration for publishing events to NATS
+type NATSConfig struct {
+	// Addr is the host:port of the NATS server
+	Addr string `json:"addr"`
+	// Subject is the prefix of the subject that events are published on. The event
+	// type is appended to it, like clusterm.events.node.discovered
+	Subject  string `json:"subject"`
+	User     string `json:"user,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+// NATSDriver implements the publisher driver for NATS, using the NATS client protocol.
+// A connection is made for publishing each event, as the events are few and far between.
+type NATSDriver struct {
+	config NATSConfig
+}
+
+// NewNATSDriver initializes and returns an instance of NATS publisher driver
+func NewNATSDriver(config NATSConfig) *NATSDriver {
+	if config.Subject == "" {
+		config.Subject = "clusterm.events"
+	}
+	return &NATSDriver{
+		config: config,
+	}
+}
+
+type natsConnectOptions struct {
+	Verbose  bool   `json:"verbose"`
+	Pedantic bool   `json:"pedantic"`
+	Name     string `json:"name"`
+	User     string `json:"user,omitempty"`
+	Pass     string `json:"pass,omitempty"`
+	Token    string `json:"auth_token,omitempty"`
+}
+
+// subject returns the subject that the event is published on
+func (d *NATSDriver) subject(e *Event) string {
+	return d.config.Subject + "." + string(e.Type)
+}
+
+// Send implements the send interface of publisher driver
+func (d *NATSDriver) Send(e *Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	opts, err := json.Marshal(&natsConnectOptions{
+		Name:  "clusterm",
+		User:  d.config.User,
+		Pass:  d.config.Password,
+		Token: d.config.Token,
+	})
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout("tcp", d.config.Addr, 10*time.Second)
+	if err != nil {
+		return errored.Errorf("failed to connect to nats server at %q. Error: %v", d.config.Addr, err)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(30 * time.Second)); err != nil {
+		return err
+	}
+	r := bufio.NewReader(conn)
+
+	// the server sends it's INFO on connect
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return errored.Errorf("failed to read nats server info. Error: %v", err)
+	}
+	if !strings.HasPrefix(line, "INFO") {
+		return errored.Errorf("unexpected nats server info: %q", strings.TrimSpace(line))
+	}
+
+	// the PING ensures that the server has processed the CONNECT and PUB, as it responds
+	// with a PONG only after them or an -ERR on failure
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\nPUB %s %d\r\n%s\r\nPING\r\n", opts, d.subject(e),
+		len(payload), payload); err != nil {
+		return errored.Errorf("failed to publish to nats server. Error: %v", err)
+	}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return errored.Errorf("failed to read nats server response. Error: %v", err)
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PONG":
+			return nil
+		case strings.HasPrefix(line, "-ERR"):
+			return errored.Errorf("nats server returned error: %s", line)
+		}
+		// skip any other protocol messages like +OK or PING
+	}
+}
@@ -0,0 +1,145 @@
+package publisher
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// SchemaVersion is the version of the event schema. It's changed on making
+// incompatible changes to the schema.
+const SchemaVersion = "1"
+
+// EventType enumerates the node and job lifecycle events that are published
+type EventType string
+
+const (
+	// NodeDiscovered event is published when a node is discovered by monitoring subsystem
+	NodeDiscovered EventType = "node.discovered"
+	// NodeDisappeared event is published when a node disappears from monitoring subsystem
+	NodeDisappeared EventType = "node.disappeared"
+	// NodeStatusChanged event is published when a node's lifecycle status changes in inventory
+	NodeStatusChanged EventType = "node.status_changed"
+	// JobStarted event is published when a job starts running
+	JobStarted EventType = "job.started"
+	// JobCompleted event is published when a job completes successfully
+	JobCompleted EventType = "job.completed"
+	// JobFailed event is published when a job fails or is cancelled
+	JobFailed EventType = "job.failed"
+)
+
+// NodeInfo is the node's info published with node events
+type NodeInfo struct {
+	Name      string `json:"name"`
+	Addr      string `json:"addr,omitempty"`
+	HostGroup string `json:"host_group,omitempty"`
+	Status    string `json:"status,omitempty"`
+	State     string `json:"state,omitempty"`
+}
+
+// JobInfo is the job's info published with job events
+type JobInfo struct {
+	ID        string   `json:"id"`
+	Desc      string   `json:"desc"`
+	EventType string   `json:"event_type,omitempty"`
+	Nodes     []string `json:"nodes,omitempty"`
+	Commit    string   `json:"commit,omitempty"`
+	Status    string   `json:"status"`
+	Error     string   `json:"error,omitempty"`
+	ErrorCode string   `json:"error_code,omitempty"`
+}
+
+// Event is the published event. The schema is documented in management/events.md
+type Event struct {
+	SchemaVersion string    `json:"schema_version"`
+	ID            string    `json:"id"`
+	Type          EventType `json:"type"`
+	Time          time.Time `json:"time"`
+	Node          *NodeInfo `json:"node,omitempty"`
+	Job           *JobInfo  `json:"job,omitempty"`
+}
+
+// newEventID returns a random id for an event
+func newEventID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		// fallback to a time based id
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(id)
+}
+
+// NewEvent returns an event of the specified type with a unique id and current time.
+// The caller is expected to fill the node or job info.
+func NewEvent(t EventType) *Event {
+	return &Event{
+		SchemaVersion: SchemaVersion,
+		ID:            newEventID(),
+		Type:          t,
+		Time:          time.Now(),
+	}
+}
+
+// key returns the key that the event is published with. The events of a node are
+// published with the same key, so that their order is retained where the broker
+// partitions the events by key.
+func (e *Event) key() string {
+	if e.Node != nil {
+		return e.Node.Name
+	}
+	return "job"
+}
+
+// Subsys provides the following services to the cluster manager:
+// - Interface to publish the node and job lifecycle events to a message broker.
+type Subsys interface {
+	// Publish enqueues the event for publishing. It doesn't block on publishing
+	// the event and the failures are only logged.
+	Publish(e *Event)
+}
+
+// Driver is implemented by the message broker specific drivers
+type Driver interface {
+	// Send publishes the event to the message broker
+	Send(e *Event) error
+}
+
+// queueSize is the number of events that can be pending before the newer events are dropped
+const queueSize = 1000
+
+// AsyncPublisher implements the publisher subsystem by publishing the events
+// through the driver, in the order they are enqueued
+type AsyncPublisher struct {
+	driver Driver
+	queue  chan *Event
+}
+
+// NewAsyncPublisher initializes and returns an instance of publisher subsystem
+func NewAsyncPublisher(driver Driver) *AsyncPublisher {
+	p := &AsyncPublisher{
+		driver: driver,
+		queue:  make(chan *Event, queueSize),
+	}
+	go p.publishLoop()
+	return p
+}
+
+func (p *AsyncPublisher) publishLoop() {
+	for e := range p.queue {
+		if err := p.driver.Send(e); err != nil {
+			logrus.Errorf("failed to publish event %q (id: %s). Error: %v", e.Type, e.ID, err)
+		}
+	}
+}
+
+// Publish implements the publish interface of publisher subsystem
+func (p *AsyncPublisher) Publish(e *Event) {
+	select {
+	case p.queue <- e:
+	default:
+		logrus.Errorf("event queue is full, dropping event %q (id: %s)", e.Type, e.ID)
+	}
+}
@@ -0,0 +1,161 @@
+// +build unittest
+
+package publisher
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type publisherSuite struct {
+}
+
+var _ = Suite(&publisherSuite{})
+
+// fakeNATS serves a single connection of nats client protocol. It responds to PING
+// with the specified response and returns the received PUB subject and payload on pubCh
+func fakeNATS(c *C, pingResp string) (string, chan []string, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	pubCh := make(chan []string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		fmt.Fprintf(conn, "INFO {\"server_id\":\"test\"}\r\n")
+		r := bufio.NewReader(conn)
+		pub := []string{}
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimSpace(line)
+			switch {
+			case strings.HasPrefix(line, "PUB"):
+				payload, _ := r.ReadString('\n')
+				pub = append(pub, strings.Fields(line)[1], strings.TrimSpace(payload))
+			case line == "PING":
+				pubCh <- pub
+				fmt.Fprintf(conn, "%s\r\n", pingResp)
+				return
+			}
+		}
+	}()
+	return l.Addr().String(), pubCh, func() { l.Close() }
+}
+
+func (s *publisherSuite) TestEventJSON(c *C) {
+	e := NewEvent(NodeStatusChanged)
+	e.Node = &NodeInfo{Name: "node1", Addr: "1.2.3.4", HostGroup: "service-master", Status: "Allocated",
+		State: "Discovered"}
+	c.Assert(e.ID, Not(Equals), NewEvent(NodeStatusChanged).ID)
+
+	out, err := json.Marshal(e)
+	c.Assert(err, IsNil)
+	parsed := map[string]interface{}{}
+	c.Assert(json.Unmarshal(out, &parsed), IsNil)
+	c.Assert(parsed["schema_version"], Equals, SchemaVersion)
+	c.Assert(parsed["type"], Equals, "node.status_changed")
+	c.Assert(parsed["job"], IsNil)
+	c.Assert(parsed["node"], DeepEquals, map[string]interface{}{
+		"name": "node1", "addr": "1.2.3.4", "host_group": "service-master", "status": "Allocated",
+		"state": "Discovered",
+	})
+}
+
+func (s *publisherSuite) TestNATSSend(c *C) {
+	addr, pubCh, stop := fakeNATS(c, "PONG")
+	defer stop()
+
+	d := NewNATSDriver(NATSConfig{Addr: addr})
+	e := NewEvent(JobFailed)
+	e.Job = &JobInfo{Desc: "commissionEvent", Status: "Errored", Error: "failed"}
+	c.Assert(d.Send(e), IsNil)
+
+	pub := <-pubCh
+	c.Assert(pub[0], Equals, "clusterm.events.job.failed")
+	sent := &Event{}
+	c.Assert(json.Unmarshal([]byte(pub[1]), sent), IsNil)
+	c.Assert(sent.ID, Equals, e.ID)
+	c.Assert(sent.Job, DeepEquals, e.Job)
+}
+
+func (s *publisherSuite) TestNATSSendError(c *C) {
+	addr, _, stop := fakeNATS(c, "-ERR 'Authorization Violation'")
+	defer stop()
+
+	d := NewNATSDriver(NATSConfig{Addr: addr})
+	err := d.Send(NewEvent(JobStarted))
+	c.Assert(err, NotNil)
+	c.Assert(strings.Contains(err.Error(), "Authorization Violation"), Equals, true)
+}
+
+func (s *publisherSuite) TestKafkaSend(c *C) {
+	var (
+		path, contentType string
+		records           kafkaRecords
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		contentType = r.Header.Get("Content-Type")
+		if err := json.NewDecoder(r.Body).Decode(&records); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer ts.Close()
+
+	d := NewKafkaDriver(KafkaConfig{RESTProxyURL: ts.URL})
+	e := NewEvent(NodeDiscovered)
+	e.Node = &NodeInfo{Name: "node1"}
+	c.Assert(d.Send(e), IsNil)
+	c.Assert(path, Equals, "/topics/clusterm-events")
+	c.Assert(contentType, Equals, "application/vnd.kafka.json.v2+json")
+	c.Assert(records.Records, HasLen, 1)
+	c.Assert(records.Records[0].Key, Equals, "node1")
+	c.Assert(records.Records[0].Value.ID, Equals, e.ID)
+}
+
+func (s *publisherSuite) TestKafkaSendError(c *C) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	d := NewKafkaDriver(KafkaConfig{RESTProxyURL: ts.URL})
+	c.Assert(d.Send(NewEvent(JobStarted)), NotNil)
+}
+
+type fakeDriver struct {
+	sent chan *Event
+}
+
+func (d *fakeDriver) Send(e *Event) error {
+	d.sent <- e
+	return nil
+}
+
+func (s *publisherSuite) TestAsyncPublisherOrder(c *C) {
+	d := &fakeDriver{sent: make(chan *Event, 10)}
+	p := NewAsyncPublisher(d)
+	events := []*Event{NewEvent(JobStarted), NewEvent(NodeStatusChanged), NewEvent(JobCompleted)}
+	for _, e := range events {
+		p.Publish(e)
+	}
+	for _, e := range events {
+		c.Assert((<-d.sent).ID, Equals, e.ID)
+	}
+}
@@ -0,0 +1,78 @@
+package remotelog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/contiv/errored"
+)
+
+// FluentdConfig denotes the configuration for shipping logs to fluentd through it's
+// http input plugin
+type FluentdConfig struct {
+	// URL is the url of fluentd's http input, like http://localhost:9880
+	URL string `json:"url"`
+	// Tag is the prefix of the tag of the entries. The entry's source is appended
+	// to it, like clusterm.daemon and clusterm.job
+	Tag string `json:"tag"`
+}
+
+// FluentdDriver implements the remote logging driver for fluentd
+type FluentdDriver struct {
+	config FluentdConfig
+	client *http.Client
+}
+
+// NewFluentdDriver initializes and returns an instance of fluentd driver
+func NewFluentdDriver(config FluentdConfig) *FluentdDriver {
+	if config.Tag == "" {
+		config.Tag = "clusterm"
+	}
+	return &FluentdDriver{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// record returns the fluentd record for the entry. The fields are added as top
+// level keys of the record
+func record(e *Entry) map[string]string {
+	r := map[string]string{}
+	for k, v := range e.Fields {
+		r[k] = v
+	}
+	r["source"] = e.Source
+	r["level"] = e.Level
+	r["message"] = e.Message
+	return r
+}
+
+// Send implements the send interface of remote logging driver
+func (d *FluentdDriver) Send(e *Entry) error {
+	var reqBody bytes.Buffer
+	if err := json.NewEncoder(&reqBody).Encode(record(e)); err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/%s.%s?time=%.3f", strings.TrimSuffix(d.config.URL, "/"), d.config.Tag, e.Source,
+		float64(e.Time.UnixNano())/float64(time.Second))
+	resp, err := d.client.Post(url, "application/json", &reqBody)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			body = []byte{}
+		}
+		return errored.Errorf("fluentd request failed. Status code %d unexpected. Response body: %q",
+			resp.StatusCode, body)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package remotelog
+
+import (
+	"fmt"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Hook is a logrus hook that ships the daemon logs
+type Hook struct {
+	subsys Subsys
+}
+
+// NewHook returns a logrus hook that ships the log entries through the remote logging subsystem
+func NewHook(subsys Subsys) *Hook {
+	return &Hook{
+		subsys: subsys,
+	}
+}
+
+// Levels implements the levels interface of logrus hook
+func (h *Hook) Levels() []logrus.Level {
+	return []logrus.Level{
+		logrus.PanicLevel,
+		logrus.FatalLevel,
+		logrus.ErrorLevel,
+		logrus.WarnLevel,
+		logrus.InfoLevel,
+		logrus.DebugLevel,
+	}
+}
+
+// Fire implements the fire interface of logrus hook
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	fields := map[string]string{}
+	for k, v := range entry.Data {
+		fields[k] = fmt.Sprintf("%v", v)
+	}
+	h.subsys.Ship(&Entry{
+		Time:    entry.Time,
+		Source:  SourceDaemon,
+		Level:   entry.Level.String(),
+		Message: entry.Message,
+		Fields:  fields,
+	})
+	return nil
+}
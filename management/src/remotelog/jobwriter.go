@@ -0,0 +1,70 @@
+package remotelog
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JobWriter ships the job logs written to it, a line per entry, with the job's
+// structured fields
+type JobWriter struct {
+	sync.Mutex
+	subsys Subsys
+	fields map[string]string
+	buf    bytes.Buffer
+}
+
+// NewJobWriter returns a writer for shipping the logs of a job. The nodes are
+// joined as a comma separated list in the node field.
+func NewJobWriter(subsys Subsys, jobID, eventType string, nodes []string) *JobWriter {
+	return &JobWriter{
+		subsys: subsys,
+		fields: map[string]string{
+			FieldJobID:     jobID,
+			FieldEventType: eventType,
+			FieldNode:      strings.Join(nodes, ","),
+		},
+	}
+}
+
+func (w *JobWriter) ship(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	w.subsys.Ship(&Entry{
+		Time:    time.Now(),
+		Source:  SourceJob,
+		Level:   "info",
+		Message: line,
+		Fields:  w.fields,
+	})
+}
+
+// Write implements the io.Writer interface. The complete lines are shipped right
+// away, while a partial line is held till it is completed or the writer is closed.
+func (w *JobWriter) Write(p []byte) (int, error) {
+	w.Lock()
+	defer w.Unlock()
+	w.buf.Write(p)
+	for {
+		line, err := w.buf.ReadString('\n')
+		if err != nil {
+			// put back the partial line
+			w.buf.WriteString(line)
+			break
+		}
+		w.ship(strings.TrimSuffix(line, "\n"))
+	}
+	return len(p), nil
+}
+
+// Close ships the pending partial line, if any
+func (w *JobWriter) Close() error {
+	w.Lock()
+	defer w.Unlock()
+	w.ship(w.buf.String())
+	w.buf.Reset()
+	return nil
+}
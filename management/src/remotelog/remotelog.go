@@ -0,0 +1,83 @@
+package remotelog
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// the sources of the shipped log entries
+const (
+	// SourceDaemon denotes the clusterm daemon's logs
+	SourceDaemon = "daemon"
+	// SourceJob denotes the logs of a job
+	SourceJob = "job"
+)
+
+// the structured fields of the shipped job log entries
+const (
+	FieldJobID     = "job_id"
+	FieldNode      = "node"
+	FieldEventType = "event_type"
+)
+
+// Entry denotes a log entry that is shipped to the remote log collector
+type Entry struct {
+	Time    time.Time
+	Source  string
+	Level   string
+	Message string
+	Fields  map[string]string
+}
+
+// Subsys provides the following services to the cluster manager:
+// - Interface to ship the daemon and job logs to a remote log collector.
+type Subsys interface {
+	// Ship enqueues the entry for shipping. It doesn't block on shipping the entry
+	Ship(e *Entry)
+}
+
+// Driver is implemented by the log collector specific drivers
+type Driver interface {
+	// Send ships the entry to the log collector
+	Send(e *Entry) error
+}
+
+// queueSize is the number of entries that can be pending before the newer entries are dropped
+const queueSize = 10000
+
+// AsyncShipper implements the remote logging subsystem by shipping the entries through
+// the driver, in the order they are enqueued
+type AsyncShipper struct {
+	driver Driver
+	queue  chan *Entry
+}
+
+// NewAsyncShipper initializes and returns an instance of remote logging subsystem
+func NewAsyncShipper(driver Driver) *AsyncShipper {
+	s := &AsyncShipper{
+		driver: driver,
+		queue:  make(chan *Entry, queueSize),
+	}
+	go s.shipLoop()
+	return s
+}
+
+func (s *AsyncShipper) shipLoop() {
+	for e := range s.queue {
+		if err := s.driver.Send(e); err != nil {
+			// the failure is not logged through logrus, as the daemon logs are
+			// shipped as well and it would loop back here
+			fmt.Fprintf(os.Stderr, "failed to ship log entry to remote log collector. Error: %v\n", err)
+		}
+	}
+}
+
+// Ship implements the ship interface of remote logging subsystem
+func (s *AsyncShipper) Ship(e *Entry) {
+	select {
+	case s.queue <- e:
+	default:
+		// drop the entry rather than block the logging
+	}
+}
@@ -0,0 +1,165 @@
+// +build unittest
+
+package remotelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type remotelogSuite struct {
+}
+
+var _ = Suite(&remotelogSuite{})
+
+// recordingSubsys records the shipped entries
+type recordingSubsys struct {
+	sync.Mutex
+	entries []*Entry
+}
+
+func (s *recordingSubsys) Ship(e *Entry) {
+	s.Lock()
+	defer s.Unlock()
+	s.entries = append(s.entries, e)
+}
+
+func (s *remotelogSuite) TestSyslogFormat(c *C) {
+	d := NewSyslogDriver(SyslogConfig{Addr: "localhost:514"})
+	d.hostname = "host1"
+	e := &Entry{
+		Time:    time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC),
+		Source:  SourceJob,
+		Level:   "error",
+		Message: "task failed",
+		Fields: map[string]string{
+			FieldJobID:     "abcd",
+			FieldNode:      "node1,node2",
+			FieldEventType: "commission",
+			"quote":        `a"b]`,
+		},
+	}
+	exptd := fmt.Sprintf(`<27>1 2016-01-02T03:04:05Z host1 clusterm %d job `+
+		`[clusterm@32473 event_type="commission" job_id="abcd" node="node1,node2" quote="a\"b\]"] task failed`,
+		os.Getpid())
+	c.Assert(d.format(e), Equals, exptd)
+
+	// no fields and unknown level
+	e = &Entry{Time: e.Time, Source: SourceDaemon, Level: "trace", Message: "msg"}
+	exptd = fmt.Sprintf(`<30>1 2016-01-02T03:04:05Z host1 clusterm %d daemon - msg`, os.Getpid())
+	c.Assert(d.format(e), Equals, exptd)
+}
+
+func (s *remotelogSuite) TestSyslogSendUDP(c *C) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	defer pc.Close()
+
+	d := NewSyslogDriver(SyslogConfig{Addr: pc.LocalAddr().String(), Tag: "test"})
+	c.Assert(d.Send(&Entry{Time: time.Now(), Source: SourceDaemon, Level: "info", Message: "hello"}), IsNil)
+
+	buf := make([]byte, 1024)
+	c.Assert(pc.SetReadDeadline(time.Now().Add(5*time.Second)), IsNil)
+	n, _, err := pc.ReadFrom(buf)
+	c.Assert(err, IsNil)
+	msg := string(buf[:n])
+	c.Assert(strings.HasPrefix(msg, "<30>1 "), Equals, true, Commentf("msg: %s", msg))
+	c.Assert(strings.HasSuffix(msg, " test "+fmt.Sprintf("%d", os.Getpid())+" daemon - hello"), Equals, true,
+		Commentf("msg: %s", msg))
+}
+
+func (s *remotelogSuite) TestFluentdSend(c *C) {
+	var (
+		path   string
+		tm     string
+		record map[string]string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		tm = r.URL.Query().Get("time")
+		json.NewDecoder(r.Body).Decode(&record)
+	}))
+	defer srv.Close()
+
+	d := NewFluentdDriver(FluentdConfig{URL: srv.URL + "/"})
+	e := &Entry{
+		Time:    time.Unix(1451703845, 0),
+		Source:  SourceJob,
+		Level:   "info",
+		Message: "task ok",
+		Fields:  map[string]string{FieldJobID: "abcd"},
+	}
+	c.Assert(d.Send(e), IsNil)
+	c.Assert(path, Equals, "/clusterm.job")
+	c.Assert(tm, Equals, "1451703845.000")
+	c.Assert(record, DeepEquals, map[string]string{
+		"source":   SourceJob,
+		"level":    "info",
+		"message":  "task ok",
+		FieldJobID: "abcd",
+	})
+}
+
+func (s *remotelogSuite) TestFluentdSendFailure(c *C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad tag", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	d := NewFluentdDriver(FluentdConfig{URL: srv.URL})
+	err := d.Send(&Entry{Time: time.Now(), Source: SourceDaemon, Level: "info", Message: "msg"})
+	c.Assert(err, ErrorMatches, ".*Status code 400 unexpected.*bad tag.*")
+}
+
+func (s *remotelogSuite) TestJobWriter(c *C) {
+	rs := &recordingSubsys{}
+	w := NewJobWriter(rs, "abcd", "commission", []string{"node1", "node2"})
+
+	fmt.Fprintf(w, "line1\nli")
+	c.Assert(rs.entries, HasLen, 1)
+	fmt.Fprintf(w, "ne2\n\n")
+	c.Assert(rs.entries, HasLen, 2)
+	fmt.Fprintf(w, "line3")
+	c.Assert(rs.entries, HasLen, 2)
+	c.Assert(w.Close(), IsNil)
+	c.Assert(rs.entries, HasLen, 3)
+
+	exptdFields := map[string]string{
+		FieldJobID:     "abcd",
+		FieldEventType: "commission",
+		FieldNode:      "node1,node2",
+	}
+	for i, e := range rs.entries {
+		c.Assert(e.Message, Equals, fmt.Sprintf("line%d", i+1))
+		c.Assert(e.Source, Equals, SourceJob)
+		c.Assert(e.Fields, DeepEquals, exptdFields)
+	}
+}
+
+func (s *remotelogSuite) TestHook(c *C) {
+	rs := &recordingSubsys{}
+	l := logrus.New()
+	l.Out = &strings.Builder{}
+	l.Hooks.Add(NewHook(rs))
+	l.WithField("node", "node1").Warnf("node %s is down", "node1")
+
+	c.Assert(rs.entries, HasLen, 1)
+	c.Assert(rs.entries[0].Source, Equals, SourceDaemon)
+	c.Assert(rs.entries[0].Level, Equals, "warning")
+	c.Assert(rs.entries[0].Message, Equals, "node node1 is down")
+	c.Assert(rs.entries[0].Fields, DeepEquals, map[string]string{"node": "node1"})
+}
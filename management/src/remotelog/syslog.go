@@ -0,0 +1,116 @@
+package remotelog
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/contiv/cluster/management/src/netwriter"
+)
+
+// SyslogConfig denotes the configuration for shipping logs to a remote syslog server.
+// The logs are sent in RFC 5424 format, with the fields as structured data.
+type SyslogConfig struct {
+	// Network is udp or tcp
+	Network string `json:"network"`
+	Addr    string `json:"addr"`
+	// Tag is the app-name of the syslog messages
+	Tag string `json:"tag"`
+}
+
+// sdID is the structured data id of the fields. 32473 is the private enterprise
+// number reserved for documentation use.
+const sdID = "clusterm@32473"
+
+// facilityDaemon is the syslog facility of the messages
+const facilityDaemon = 3
+
+var severities = map[string]int{
+	"panic":   0,
+	"fatal":   2,
+	"error":   3,
+	"warning": 4,
+	"info":    6,
+	"debug":   7,
+}
+
+// SyslogDriver implements the remote logging driver for syslog
+type SyslogDriver struct {
+	config   SyslogConfig
+	hostname string
+	writer   *netwriter.Writer
+}
+
+// NewSyslogDriver initializes and returns an instance of syslog driver
+func NewSyslogDriver(config SyslogConfig) *SyslogDriver {
+	if config.Network == "" {
+		config.Network = "udp"
+	}
+	if config.Tag == "" {
+		config.Tag = "clusterm"
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "-"
+	}
+	return &SyslogDriver{
+		config:   config,
+		hostname: hostname,
+		writer:   netwriter.New("syslog server", config.Network, config.Addr),
+	}
+}
+
+// escapeSDValue escapes the characters that are special in a structured data param value
+func escapeSDValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(v)
+}
+
+// sdName returns the name with the characters that are not allowed in a structured
+// data param name removed
+func sdName(n string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || r == '=' || r == ']' || r == '"' {
+			return -1
+		}
+		return r
+	}, n)
+}
+
+// format returns the RFC 5424 formatted message for the entry
+func (d *SyslogDriver) format(e *Entry) string {
+	severity, ok := severities[e.Level]
+	if !ok {
+		severity = severities["info"]
+	}
+	keys := []string{}
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	sd := "-"
+	if len(keys) > 0 {
+		params := []string{sdID}
+		for _, k := range keys {
+			if name := sdName(k); name != "" {
+				params = append(params, fmt.Sprintf(`%s="%s"`, name, escapeSDValue(e.Fields[k])))
+			}
+		}
+		sd = "[" + strings.Join(params, " ") + "]"
+	}
+	return fmt.Sprintf("<%d>1 %s %s %s %d %s %s %s", facilityDaemon*8+severity,
+		e.Time.UTC().Format(time.RFC3339Nano), d.hostname, d.config.Tag, os.Getpid(), e.Source, sd, e.Message)
+}
+
+// Send implements the send interface of remote logging driver. The connection is
+// reused across entries and is re-established on failure.
+func (d *SyslogDriver) Send(e *Entry) error {
+	msg := d.format(e)
+	if d.config.Network == "tcp" {
+		// messages are newline delimited on tcp
+		msg += "\n"
+	}
+	_, err := d.writer.Write([]byte(msg))
+	return err
+}
@@ -0,0 +1,123 @@
+// Package retry implements the retries of the calls to the backends, with an
+// exponential backoff and jitter between the attempts
+package retry
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/contiv/cluster/management/src/clustererr"
+)
+
+// Policy denotes the retry behavior on failure of a call
+type Policy struct {
+	// MaxAttempts is the max times a call is attempted, including the first one.
+	// The call is attempted until it succeeds when it is not set
+	MaxAttempts int `json:"max_attempts"`
+	// InitialBackoffMsecs is the time to wait before the first retry, it is doubled
+	// for every subsequent retry upto MaxBackoffMsecs
+	InitialBackoffMsecs int `json:"initial_backoff_msecs"`
+	MaxBackoffMsecs     int `json:"max_backoff_msecs"`
+	// Jitter is the fraction of the backoff by which it is randomly reduced, so
+	// that the callers failing together don't retry in lock-step. It is between 0 and 1
+	Jitter float64 `json:"jitter"`
+}
+
+// Backoff returns the time to wait before the specified retry, without jitter
+func (p Policy) Backoff(retry int) time.Duration {
+	d := time.Duration(p.InitialBackoffMsecs) * time.Millisecond
+	max := time.Duration(p.MaxBackoffMsecs) * time.Millisecond
+	for i := 1; i < retry && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d
+}
+
+var (
+	randMutex sync.Mutex
+	random    = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// jitter returns the backoff randomly reduced by upto the jitter fraction
+func (p Policy) jitter(d time.Duration) time.Duration {
+	j := p.Jitter
+	if j <= 0 {
+		return d
+	}
+	if j > 1 {
+		j = 1
+	}
+	randMutex.Lock()
+	f := random.Float64()
+	randMutex.Unlock()
+	return d - time.Duration(float64(d)*j*f)
+}
+
+// OnRetry is called before a call is retried with the attempt being made, the
+// time waited before it and the error of the previous attempt
+type OnRetry func(attempt int, backoff time.Duration, err error)
+
+// Retrier retries the calls as per a policy
+type Retrier struct {
+	Policy Policy
+	// Retriable returns true if the call shall be retried on the error. All the
+	// errors are retried when it is not set
+	Retriable func(err error) bool
+	// OnRetry, if set, is called before every retry
+	OnRetry OnRetry
+	// Sleep waits for the backoff before a retry. It is time.Sleep when not set
+	Sleep func(time.Duration)
+}
+
+// Do calls fn until it succeeds, it fails with an error that is not retriable
+// or the attempts are exhausted. The error of the last attempt is returned with
+// it's code and node retained. An error that aggregates the errors of multiple
+// nodes is returned as is, so that the nodes that failed can be told apart.
+func (r Retrier) Do(fn func() error) error {
+	sleep := r.Sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+	var err error
+	for attempt := 1; r.Policy.MaxAttempts <= 0 || attempt <= r.Policy.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			backoff := r.Policy.jitter(r.Policy.Backoff(attempt - 1))
+			if r.OnRetry != nil {
+				r.OnRetry(attempt, backoff, err)
+			}
+			sleep(backoff)
+		}
+		if err = fn(); err == nil {
+			return nil
+		}
+		if r.Retriable != nil && !r.Retriable(err) {
+			return err
+		}
+	}
+	if _, ok := err.(aggregate); ok || r.Policy.MaxAttempts <= 1 {
+		return err
+	}
+	return clustererr.Wrapf(clustererr.Internal, "", err, "giving up after %d attempt(s). Last error: %v",
+		r.Policy.MaxAttempts, err)
+}
+
+// aggregate is implemented by the errors that aggregate the errors of multiple
+// nodes, keyed by the node name
+type aggregate interface {
+	Errors() map[string]error
+}
+
+// IsBackendError returns true if the error is due to a backend or a timeout,
+// which may go away when the call is retried. The errors due to an invalid
+// request or a conflicting state are not.
+func IsBackendError(err error) bool {
+	switch clustererr.CodeOf(err) {
+	case clustererr.Inventory, clustererr.Provisioner, clustererr.Monitor, clustererr.Timeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+// +build unittest
+
+package retry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/contiv/cluster/management/src/clustererr"
+	"github.com/contiv/errored"
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type retrySuite struct {
+}
+
+var _ = Suite(&retrySuite{})
+
+type testNodeErrors map[string]error
+
+func (e testNodeErrors) Error() string {
+	return "node(s) failed"
+}
+
+func (e testNodeErrors) Errors() map[string]error {
+	return e
+}
+
+// testRetrier returns a retrier that records the backoffs instead of sleeping
+func testRetrier(policy Policy, backoffs *[]time.Duration) Retrier {
+	return Retrier{
+		Policy:    policy,
+		Retriable: IsBackendError,
+		Sleep:     func(d time.Duration) { *backoffs = append(*backoffs, d) },
+	}
+}
+
+// failingFn returns a func that fails with err for the first failures calls
+func failingFn(calls *int, failures int, err error) func() error {
+	return func() error {
+		*calls++
+		if *calls <= failures {
+			return err
+		}
+		return nil
+	}
+}
+
+func (s *retrySuite) TestBackoff(c *C) {
+	p := Policy{InitialBackoffMsecs: 100, MaxBackoffMsecs: 1000}
+	c.Assert(p.Backoff(1), Equals, 100*time.Millisecond)
+	c.Assert(p.Backoff(2), Equals, 200*time.Millisecond)
+	c.Assert(p.Backoff(4), Equals, 800*time.Millisecond)
+	c.Assert(p.Backoff(5), Equals, 1000*time.Millisecond)
+	c.Assert(p.Backoff(100), Equals, 1000*time.Millisecond)
+}
+
+func (s *retrySuite) TestJitter(c *C) {
+	p := Policy{Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		d := p.jitter(time.Second)
+		c.Assert(d <= time.Second, Equals, true)
+		c.Assert(d >= 500*time.Millisecond, Equals, true)
+	}
+	c.Assert(Policy{}.jitter(time.Second), Equals, time.Second)
+	c.Assert(Policy{Jitter: 2}.jitter(time.Second) >= 0, Equals, true)
+}
+
+func (s *retrySuite) TestDoSuccessAfterRetries(c *C) {
+	backoffs := []time.Duration{}
+	retries := []int{}
+	r := testRetrier(Policy{MaxAttempts: 5, InitialBackoffMsecs: 10, MaxBackoffMsecs: 25}, &backoffs)
+	r.OnRetry = func(attempt int, backoff time.Duration, err error) {
+		c.Assert(err, ErrorMatches, "connection refused")
+		retries = append(retries, attempt)
+	}
+	calls := 0
+	c.Assert(r.Do(failingFn(&calls, 3, clustererr.New(clustererr.Inventory, "connection refused"))), IsNil)
+	c.Assert(calls, Equals, 4)
+	c.Assert(retries, DeepEquals, []int{2, 3, 4})
+	c.Assert(backoffs, DeepEquals, []time.Duration{
+		10 * time.Millisecond, 20 * time.Millisecond, 25 * time.Millisecond})
+}
+
+func (s *retrySuite) TestDoGivesUp(c *C) {
+	backoffs := []time.Duration{}
+	r := testRetrier(Policy{MaxAttempts: 3, InitialBackoffMsecs: 1, MaxBackoffMsecs: 1}, &backoffs)
+	calls := 0
+	err := r.Do(failingFn(&calls, 10, clustererr.NewForNode(clustererr.Inventory, "node1", "connection refused")))
+	c.Assert(err, ErrorMatches, "giving up after 3 attempt\\(s\\). Last error: connection refused")
+	c.Assert(clustererr.CodeOf(err), Equals, clustererr.Inventory)
+	c.Assert(clustererr.NodeOf(err), Equals, "node1")
+	c.Assert(calls, Equals, 3)
+	c.Assert(backoffs, HasLen, 2)
+
+	// the error is returned as is when the call is attempted only once
+	r.Policy.MaxAttempts = 1
+	calls = 0
+	err = r.Do(failingFn(&calls, 10, clustererr.New(clustererr.Inventory, "connection refused")))
+	c.Assert(err, ErrorMatches, "connection refused")
+	c.Assert(calls, Equals, 1)
+
+	// the aggregates are returned as is
+	r.Policy.MaxAttempts = 2
+	calls = 0
+	nerrs := testNodeErrors{"node1": clustererr.New(clustererr.Inventory, "connection refused")}
+	err = r.Do(failingFn(&calls, 10, nerrs))
+	c.Assert(err, DeepEquals, nerrs)
+	c.Assert(calls, Equals, 2)
+}
+
+func (s *retrySuite) TestDoNotRetriable(c *C) {
+	backoffs := []time.Duration{}
+	r := testRetrier(Policy{MaxAttempts: 3}, &backoffs)
+	calls := 0
+	err := r.Do(failingFn(&calls, 10, clustererr.New(clustererr.Conflict, "transition not allowed")))
+	c.Assert(err, ErrorMatches, "transition not allowed")
+	c.Assert(calls, Equals, 1)
+	c.Assert(backoffs, HasLen, 0)
+
+	// all errors are retried when retriable is not set
+	r.Retriable = nil
+	calls = 0
+	c.Assert(r.Do(failingFn(&calls, 2, errored.Errorf("foo"))), IsNil)
+	c.Assert(calls, Equals, 3)
+}
+
+func (s *retrySuite) TestIsBackendError(c *C) {
+	c.Assert(IsBackendError(clustererr.New(clustererr.Inventory, "foo")), Equals, true)
+	c.Assert(IsBackendError(clustererr.New(clustererr.Monitor, "foo")), Equals, true)
+	c.Assert(IsBackendError(clustererr.New(clustererr.Timeout, "foo")), Equals, true)
+	c.Assert(IsBackendError(clustererr.New(clustererr.Validation, "foo")), Equals, false)
+	c.Assert(IsBackendError(clustererr.New(clustererr.Conflict, "foo")), Equals, false)
+	c.Assert(IsBackendError(errored.Errorf("foo")), Equals, false)
+}
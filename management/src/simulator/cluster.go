@@ -0,0 +1,381 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/contiv/cluster/management/src/clusterm/manager"
+	"github.com/contiv/cluster/management/src/configuration"
+	fakeinv "github.com/contiv/cluster/management/src/inventory/fake"
+	"github.com/contiv/cluster/management/src/monitor"
+	"github.com/contiv/errored"
+)
+
+const (
+	defaultLabelPrefix  = "sim-node"
+	defaultWaitTimeout  = 30 * time.Second
+	waitPollInterval    = 50 * time.Millisecond
+	jobLabelActive      = "active"
+	jobLabelLast        = "last"
+	nodeStatusAllocated = "Allocated"
+)
+
+// Config denotes the configuration of a simulated cluster
+type Config struct {
+	// Addr is the address clusterm's REST service listens on. A free local port
+	// is picked when it is not set.
+	Addr string `json:"addr"`
+	// Nodes is the number of nodes that are discovered when the cluster is started
+	Nodes int `json:"nodes"`
+	// LabelPrefix is the prefix of the simulated nodes' labels
+	LabelPrefix string `json:"label_prefix"`
+	// StepMsecs is the time it takes to simulate a configuration action on a node
+	StepMsecs int `json:"step_msecs"`
+	// FailNodes are the names of the nodes, the configuration of which fails
+	FailNodes []string `json:"fail_nodes,omitempty"`
+	// MonitorCoalesceMsecs is the window over which clusterm batches the monitor
+	// events. Clusterm's default is used when it is not set.
+	MonitorCoalesceMsecs int `json:"monitor_coalesce_msecs"`
+}
+
+// Node denotes a simulated node
+type Node struct {
+	monitor.FakeNode
+}
+
+// Name returns the name of the node as known to clusterm
+func (n *Node) Name() string {
+	return n.Label + "-" + n.Serial
+}
+
+// Cluster is a cluster of simulated nodes, managed by a clusterm instance that
+// runs in-process with the fake inventory, monitoring and configuration subsystems.
+// The nodes join and fail in their own goroutines, much like the members of a
+// real cluster, which makes it useful to exercise the batching and queueing of
+// events and the master selection in end-to-end tests and demos.
+type Cluster struct {
+	sync.Mutex
+	config  Config
+	client  *manager.Client
+	monitor *monitor.FakeSubsys
+	nodes   map[string]*Node
+	count   int
+}
+
+// NewCluster initializes and returns a simulated cluster. It needs to be started
+// by calling Start.
+func NewCluster(config Config) *Cluster {
+	if config.LabelPrefix == "" {
+		config.LabelPrefix = defaultLabelPrefix
+	}
+	return &Cluster{
+		config: config,
+		nodes:  make(map[string]*Node),
+	}
+}
+
+// newNode allocates the next simulated node. It expects the lock to be held
+func (c *Cluster) newNode() *Node {
+	c.count++
+	n := &Node{
+		FakeNode: monitor.FakeNode{
+			Label:  fmt.Sprintf("%s%d", c.config.LabelPrefix, c.count),
+			Serial: fmt.Sprintf("s%d", c.count),
+			Addr:   fmt.Sprintf("10.%d.%d.%d", (c.count>>16)&0xff, (c.count>>8)&0xff, c.count&0xff),
+		},
+	}
+	c.nodes[n.Name()] = n
+	return n
+}
+
+// freeAddr returns a local address that is not in use
+func freeAddr() (string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", errored.Errorf("failed to find a free port. Error: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().String(), nil
+}
+
+// Start starts clusterm and waits for the configured nodes to be discovered. The
+// clusterm instance runs until the process exits, any failure it encounters
+// afterwards is reported on the returned channel.
+func (c *Cluster) Start() (chan error, error) {
+	c.Lock()
+	addr := c.config.Addr
+	if addr == "" {
+		var err error
+		if addr, err = freeAddr(); err != nil {
+			c.Unlock()
+			return nil, err
+		}
+	}
+	nodes := []monitor.FakeNode{}
+	names := []string{}
+	for i := 0; i < c.config.Nodes; i++ {
+		n := c.newNode()
+		nodes = append(nodes, n.FakeNode)
+		names = append(names, n.Name())
+	}
+	c.Unlock()
+
+	config := manager.DefaultConfig()
+	config.Manager.Addr = addr
+	if c.config.MonitorCoalesceMsecs > 0 {
+		config.Manager.MonitorCoalesceMsecs = c.config.MonitorCoalesceMsecs
+	}
+	config.Inventory.Fake = &fakeinv.Config{}
+	config.Monitor.Fake = &monitor.FakeConfig{Nodes: nodes}
+	config.Configuration.Fake = &configuration.FakeSubsysConfig{
+		StepMsecs: c.config.StepMsecs,
+		FailHosts: c.config.FailNodes,
+	}
+	// keep the job logs in memory, there can be multiple simulated clusters
+	config.JobLogs.Dir = ""
+
+	mgr, err := manager.NewManager(config, "")
+	if err != nil {
+		return nil, errored.Errorf("failed to initialize the manager. Error: %v", err)
+	}
+	errCh := make(chan error, 5)
+	mgr.Run(errCh)
+
+	c.Lock()
+	c.client = manager.NewClient(addr)
+	c.monitor = mgr.FakeMonitor()
+	c.config.Addr = addr
+	c.Unlock()
+
+	if err := c.WaitForNodes(names, "Unallocated", "Discovered", defaultWaitTimeout); err != nil {
+		return nil, err
+	}
+	return errCh, nil
+}
+
+// Addr returns the address of clusterm's REST service
+func (c *Cluster) Addr() string {
+	c.Lock()
+	defer c.Unlock()
+	return c.config.Addr
+}
+
+// Client returns a client to clusterm's REST service
+func (c *Cluster) Client() *manager.Client {
+	c.Lock()
+	defer c.Unlock()
+	return c.client
+}
+
+// NodeNames returns the sorted names of all the simulated nodes
+func (c *Cluster) NodeNames() []string {
+	c.Lock()
+	defer c.Unlock()
+	names := []string{}
+	for name := range c.nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// deliverMonitorEvents delivers the monitor event for each of the nodes from it's
+// own goroutine through the fake monitoring subsystem, so that the events arrive
+// at clusterm as a burst and are batched like the ones from serf
+func (c *Cluster) deliverMonitorEvents(t monitor.EventType, nodes []*Node) error {
+	c.Lock()
+	fm := c.monitor
+	c.Unlock()
+	if fm == nil {
+		return errored.Errorf("the simulated cluster is not started")
+	}
+	var wg sync.WaitGroup
+	for _, n := range nodes {
+		wg.Add(1)
+		go func(n *Node) {
+			defer wg.Done()
+			if t == monitor.Discovered {
+				fm.Join(n.FakeNode)
+			} else {
+				fm.Fail(n.FakeNode)
+			}
+		}(n)
+	}
+	wg.Wait()
+	return nil
+}
+
+// AddNodes adds count new nodes to the cluster and returns their names. The new
+// nodes join the cluster concurrently.
+func (c *Cluster) AddNodes(count int) ([]string, error) {
+	c.Lock()
+	nodes := []*Node{}
+	names := []string{}
+	for i := 0; i < count; i++ {
+		n := c.newNode()
+		nodes = append(nodes, n)
+		names = append(names, n.Name())
+	}
+	c.Unlock()
+	return names, c.deliverMonitorEvents(monitor.Discovered, nodes)
+}
+
+// lookupNodes returns the nodes with specified names
+func (c *Cluster) lookupNodes(names []string) ([]*Node, error) {
+	c.Lock()
+	defer c.Unlock()
+	nodes := []*Node{}
+	for _, name := range names {
+		n, ok := c.nodes[name]
+		if !ok {
+			return nil, errored.Errorf("node %q doesn't exist in the simulated cluster", name)
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, nil
+}
+
+// FailNodes simulates the failure of the specified nodes, for instance a power
+// loss or a network partition
+func (c *Cluster) FailNodes(names ...string) error {
+	nodes, err := c.lookupNodes(names)
+	if err != nil {
+		return err
+	}
+	return c.deliverMonitorEvents(monitor.Disappeared, nodes)
+}
+
+// RejoinNodes simulates the recovery of the specified nodes
+func (c *Cluster) RejoinNodes(names ...string) error {
+	nodes, err := c.lookupNodes(names)
+	if err != nil {
+		return err
+	}
+	return c.deliverMonitorEvents(monitor.Discovered, nodes)
+}
+
+// NodeState denotes the state of a node as reported by clusterm
+type NodeState struct {
+	Status    string
+	State     string
+	HostGroup string
+}
+
+// NodeStates returns the state of all the nodes as reported by clusterm, keyed
+// by node name
+func (c *Cluster) NodeStates() (map[string]NodeState, error) {
+	client := c.Client()
+	if client == nil {
+		return nil, errored.Errorf("the simulated cluster is not started")
+	}
+	out, err := client.GetAllNodes()
+	if err != nil {
+		return nil, err
+	}
+	nodes := map[string]struct {
+		Inv struct {
+			Status string `json:"status"`
+			State  string `json:"state"`
+		} `json:"inventory_state"`
+		Cfg struct {
+			HostGroup string `json:"host_group"`
+		} `json:"configuration_state"`
+	}{}
+	if err := json.Unmarshal(out, &nodes); err != nil {
+		return nil, errored.Errorf("failed to unmarshal nodes. Error: %v", err)
+	}
+	states := map[string]NodeState{}
+	for name, n := range nodes {
+		states[name] = NodeState{
+			Status:    n.Inv.Status,
+			State:     n.Inv.State,
+			HostGroup: n.Cfg.HostGroup,
+		}
+	}
+	return states, nil
+}
+
+// NodesInGroup returns the sorted names of the commissioned nodes in a host-group
+func (c *Cluster) NodesInGroup(hostGroup string) ([]string, error) {
+	states, err := c.NodeStates()
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for name, s := range states {
+		if s.Status == nodeStatusAllocated && s.HostGroup == hostGroup {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// waitFor polls the condition until it is true or the timeout expires
+func waitFor(desc string, timeout time.Duration, cond func() (bool, error)) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		done, err := cond()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errored.Errorf("timed out after %s waiting for %s", timeout, desc)
+		}
+		time.Sleep(waitPollInterval)
+	}
+}
+
+// WaitForNodes waits for the nodes to be in the specified inventory status and state
+func (c *Cluster) WaitForNodes(names []string, status, state string, timeout time.Duration) error {
+	desc := fmt.Sprintf("%d node(s) to be in status %q and state %q", len(names), status, state)
+	return waitFor(desc, timeout, func() (bool, error) {
+		states, err := c.NodeStates()
+		if err != nil {
+			// clusterm may not be serving yet
+			return false, nil
+		}
+		for _, name := range names {
+			s, ok := states[name]
+			if !ok || s.Status != status || s.State != state {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
+
+// WaitForJob waits for the active job to be done and returns the status of the
+// last job
+func (c *Cluster) WaitForJob(timeout time.Duration) (string, error) {
+	client := c.Client()
+	if client == nil {
+		return "", errored.Errorf("the simulated cluster is not started")
+	}
+	status := ""
+	err := waitFor("the active job to be done", timeout, func() (bool, error) {
+		if _, err := client.GetJob(jobLabelActive); err == nil {
+			return false, nil
+		}
+		out, err := client.GetJob(jobLabelLast)
+		if err != nil {
+			return false, nil
+		}
+		j := struct {
+			Status string `json:"status"`
+		}{}
+		if err := json.Unmarshal(out, &j); err != nil {
+			return false, errored.Errorf("failed to unmarshal job. Error: %v", err)
+		}
+		status = j.Status
+		return true, nil
+	})
+	return status, err
+}
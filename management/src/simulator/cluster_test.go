@@ -0,0 +1,179 @@
+// +build unittest
+
+package simulator
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/contiv/cluster/management/src/clustererr"
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type clusterSuite struct {
+}
+
+var _ = Suite(&clusterSuite{})
+
+const testTimeout = 20 * time.Second
+
+type testCluster struct {
+	*Cluster
+	errCh chan error
+}
+
+func (s *clusterSuite) startCluster(c *C, config Config) *testCluster {
+	cl := NewCluster(config)
+	errCh, err := cl.Start()
+	c.Assert(err, IsNil)
+	return &testCluster{Cluster: cl, errCh: errCh}
+}
+
+// assertRunning asserts that clusterm didn't encounter a failure
+func (tc *testCluster) assertRunning(c *C) {
+	select {
+	case err := <-tc.errCh:
+		c.Fatalf("clusterm failed. Error: %v", err)
+	default:
+	}
+}
+
+func (s *clusterSuite) TestStartDiscoversNodes(c *C) {
+	cl := s.startCluster(c, Config{Nodes: 25, MonitorCoalesceMsecs: 20})
+	names := cl.NodeNames()
+	c.Assert(names, HasLen, 25)
+	states, err := cl.NodeStates()
+	c.Assert(err, IsNil)
+	c.Assert(states, HasLen, 25)
+	for _, name := range names {
+		c.Assert(states[name].Status, Equals, "Unallocated")
+		c.Assert(states[name].State, Equals, "Discovered")
+	}
+	cl.assertRunning(c)
+}
+
+func (s *clusterSuite) TestMasterSelection(c *C) {
+	cl := s.startCluster(c, Config{Nodes: 6, StepMsecs: 1, MonitorCoalesceMsecs: 20})
+	names := cl.NodeNames()
+	clmc := cl.Client()
+
+	// workers can't be commissioned before a master
+	c.Assert(clmc.PostNodesCommission(names[2:], "", "service-worker"), NotNil)
+
+	c.Assert(clmc.PostNodesCommission(names[:2], "", "service-master"), IsNil)
+	status, err := cl.WaitForJob(testTimeout)
+	c.Assert(err, IsNil)
+	c.Assert(status, Equals, "Complete")
+	c.Assert(clmc.PostNodesCommission(names[2:], "", "service-worker"), IsNil)
+	status, err = cl.WaitForJob(testTimeout)
+	c.Assert(err, IsNil)
+	c.Assert(status, Equals, "Complete")
+
+	masters, err := cl.NodesInGroup("service-master")
+	c.Assert(err, IsNil)
+	c.Assert(masters, DeepEquals, names[:2])
+	workers, err := cl.NodesInGroup("service-worker")
+	c.Assert(err, IsNil)
+	c.Assert(workers, DeepEquals, names[2:])
+
+	// the last masters can't be decommissioned while there are workers
+	c.Assert(clmc.PostNodesDecommission(masters, ""), NotNil)
+	cl.assertRunning(c)
+}
+
+func (s *clusterSuite) TestNodesFailAndRejoin(c *C) {
+	cl := s.startCluster(c, Config{Nodes: 10, MonitorCoalesceMsecs: 20})
+	names := cl.NodeNames()
+
+	c.Assert(cl.FailNodes(names[:4]...), IsNil)
+	c.Assert(cl.WaitForNodes(names[:4], "Unallocated", "Disappeared", testTimeout), IsNil)
+	c.Assert(cl.WaitForNodes(names[4:], "Unallocated", "Discovered", testTimeout), IsNil)
+
+	c.Assert(cl.RejoinNodes(names[:4]...), IsNil)
+	c.Assert(cl.WaitForNodes(names, "Unallocated", "Discovered", testTimeout), IsNil)
+
+	added, err := cl.AddNodes(15)
+	c.Assert(err, IsNil)
+	c.Assert(added, HasLen, 15)
+	c.Assert(cl.WaitForNodes(added, "Unallocated", "Discovered", testTimeout), IsNil)
+	c.Assert(cl.NodeNames(), HasLen, 25)
+
+	c.Assert(cl.FailNodes("unknown-node"), NotNil)
+	cl.assertRunning(c)
+}
+
+func (s *clusterSuite) TestSimulatedWorkflows(c *C) {
+	cl := s.startCluster(c, Config{Nodes: 3, LabelPrefix: "node", StepMsecs: 10, FailNodes: []string{"node3-s3"},
+		MonitorCoalesceMsecs: 10})
+	clmc := cl.Client()
+	c.Assert(cl.NodeNames(), DeepEquals, []string{"node1-s1", "node2-s2", "node3-s3"})
+
+	// commission
+	c.Assert(clmc.PostNodesCommission([]string{"node1-s1", "node2-s2"}, "", "service-master"), IsNil)
+	status, err := cl.WaitForJob(testTimeout)
+	c.Assert(err, IsNil)
+	c.Assert(status, Equals, "Complete")
+	c.Assert(cl.WaitForNodes([]string{"node1-s1", "node2-s2"}, "Allocated", "Discovered", testTimeout), IsNil)
+
+	// upgrade
+	c.Assert(clmc.PostNodeUpdate("node2-s2", "", "service-worker"), IsNil)
+	status, err = cl.WaitForJob(testTimeout)
+	c.Assert(err, IsNil)
+	c.Assert(status, Equals, "Complete")
+	workers, err := cl.NodesInGroup("service-worker")
+	c.Assert(err, IsNil)
+	c.Assert(workers, DeepEquals, []string{"node2-s2"})
+
+	// a failed commission leaves the node unallocated
+	c.Assert(clmc.PostNodeCommission("node3-s3", "", "service-worker"), IsNil)
+	status, err = cl.WaitForJob(testTimeout)
+	c.Assert(err, IsNil)
+	c.Assert(status, Equals, "Errored")
+	c.Assert(cl.WaitForNodes([]string{"node3-s3"}, "Unallocated", "Discovered", testTimeout), IsNil)
+	out, err := clmc.GetJob(jobLabelLast)
+	c.Assert(err, IsNil)
+	j := struct {
+		ErrInfo *clustererr.Info `json:"error_info"`
+	}{}
+	c.Assert(json.Unmarshal(out, &j), IsNil)
+	c.Assert(j.ErrInfo, NotNil)
+	c.Assert(j.ErrInfo.Code, Equals, clustererr.Provisioner)
+	c.Assert(j.ErrInfo.Node, Equals, "node3-s3")
+
+	// the code of the error is retained in the response
+	err = clmc.PostNodeCommission("node4-s4", "", "service-worker")
+	c.Assert(clustererr.CodeOf(err), Equals, clustererr.Validation)
+	c.Assert(clustererr.NodeOf(err), Equals, "")
+	c.Assert(clustererr.InfoOf(err).Nodes[0].Node, Equals, "node4-s4")
+
+	// decommission
+	c.Assert(clmc.PostNodesDecommission([]string{"node2-s2"}, ""), IsNil)
+	status, err = cl.WaitForJob(testTimeout)
+	c.Assert(err, IsNil)
+	c.Assert(status, Equals, "Complete")
+	c.Assert(cl.WaitForNodes([]string{"node2-s2"}, "Decommissioned", "Discovered", testTimeout), IsNil)
+
+	// a node failure is reflected in the inventory
+	c.Assert(cl.FailNodes("node1-s1"), IsNil)
+	c.Assert(cl.WaitForNodes([]string{"node1-s1"}, "Allocated", "Disappeared", testTimeout), IsNil)
+	cl.assertRunning(c)
+}
+
+func (s *clusterSuite) TestMonitorEventsCoalesced(c *C) {
+	cl := s.startCluster(c, Config{Nodes: 10, MonitorCoalesceMsecs: 500})
+	names := cl.NodeNames()
+
+	// the failures are only reflected once the coalescing window elapses
+	c.Assert(cl.FailNodes(names...), IsNil)
+	states, err := cl.NodeStates()
+	c.Assert(err, IsNil)
+	for _, name := range names {
+		c.Assert(states[name].State, Equals, "Discovered")
+	}
+	c.Assert(cl.WaitForNodes(names, "Unallocated", "Disappeared", testTimeout), IsNil)
+	cl.assertRunning(c)
+}
@@ -79,7 +79,7 @@ func (s *SystemTestSuite) TestDecommissionNodesFailureDisappeared(c *C) {
 	cmdStr := fmt.Sprintf("clusterctl nodes decommission %s", nodesStr)
 	out, err := s.tbn1.RunCommandWithOutput(cmdStr)
 	s.Assert(c, err, NotNil, Commentf("output: %s", out))
-	exptStr := fmt.Sprintf(".*one or more nodes are not in discovered state.*%s.*", nodeName)
+	exptStr := fmt.Sprintf(".*node\\(s\\) failed.*%s.*is not in discovered state.*", nodeName)
 	s.assertMatch(c, exptStr, out)
 }
 
@@ -0,0 +1,232 @@
+package vault
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/contiv/errored"
+)
+
+// RefPrefix is the prefix of the variable values that refer to a secret in vault.
+// A reference is of the form vault:<secret path>#<key>, like vault:secret/registry#password
+const RefPrefix = "vault:"
+
+// Config denotes the configuration for resolving secrets from vault
+type Config struct {
+	Addr string `json:"addr"`
+	// Token is the vault token used to create the short-lived tokens that secrets are
+	// read with. It is read from TokenFile if unset.
+	Token     string `json:"token,omitempty"`
+	TokenFile string `json:"token_file,omitempty"`
+	// TokenTTLSecs is the ttl of the short-lived token
+	TokenTTLSecs int `json:"token_ttl_secs"`
+	// Policies restricts the short-lived token to the specified policies. The token
+	// inherits the policies of the configured token when it is empty
+	Policies           []string `json:"policies,omitempty"`
+	InsecureSkipVerify bool     `json:"insecure_skip_verify"`
+}
+
+// DefaultConfig returns the default configuration values for vault. These are used
+// for the values that are not set in the configuration
+func DefaultConfig() Config {
+	return Config{
+		Addr:         "https://127.0.0.1:8200",
+		TokenFile:    "/etc/default/clusterm/vault-token",
+		TokenTTLSecs: 300,
+	}
+}
+
+// Resolver resolves the secret references from vault
+type Resolver struct {
+	config Config
+	client *http.Client
+}
+
+// NewResolver initializes and returns an instance of vault secret resolver
+func NewResolver(config Config) *Resolver {
+	defaults := DefaultConfig()
+	if config.Addr == "" {
+		config.Addr = defaults.Addr
+	}
+	if config.Token == "" && config.TokenFile == "" {
+		config.TokenFile = defaults.TokenFile
+	}
+	if config.TokenTTLSecs <= 0 {
+		config.TokenTTLSecs = defaults.TokenTTLSecs
+	}
+	return &Resolver{
+		config: config,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: config.InsecureSkipVerify},
+			},
+		},
+	}
+}
+
+// IsRef returns true if the value is a secret reference
+func (r *Resolver) IsRef(val string) bool {
+	return strings.HasPrefix(val, RefPrefix)
+}
+
+// parseRef returns the secret path and key of a secret reference
+func parseRef(ref string) (string, string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(ref, RefPrefix), "#", 2)
+	if len(parts) != 2 || strings.Trim(parts[0], "/") == "" || parts[1] == "" {
+		return "", "", errored.Errorf("invalid vault reference %q, expected the form %s<path>#<key>", ref, RefPrefix)
+	}
+	return strings.Trim(parts[0], "/"), parts[1], nil
+}
+
+// Resolve returns the secret values of the references. The secrets are read with a
+// short-lived token that is created for the resolution and revoked after it.
+func (r *Resolver) Resolve(refs []string) (map[string]string, error) {
+	if len(refs) == 0 {
+		return map[string]string{}, nil
+	}
+	// validate the references before creating a token
+	for _, ref := range refs {
+		if _, _, err := parseRef(ref); err != nil {
+			return nil, err
+		}
+	}
+
+	token, err := r.createToken()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := r.request("POST", "auth/token/revoke-self", token, nil, nil); err != nil {
+			// the token expires after it's ttl anyways
+			logrus.Errorf("failed to revoke vault token. Error: %v", err)
+		}
+	}()
+
+	// a secret may be referred more than once, so read it only once
+	secrets := map[string]map[string]interface{}{}
+	vals := map[string]string{}
+	for _, ref := range refs {
+		path, key, _ := parseRef(ref)
+		data, ok := secrets[path]
+		if !ok {
+			if data, err = r.readSecret(path, token); err != nil {
+				return nil, err
+			}
+			secrets[path] = data
+		}
+		val, ok := data[key]
+		if !ok {
+			return nil, errored.Errorf("key %q not found in vault secret %q", key, path)
+		}
+		if s, ok := val.(string); ok {
+			vals[ref] = s
+		} else {
+			vals[ref] = fmt.Sprintf("%v", val)
+		}
+	}
+	return vals, nil
+}
+
+func (r *Resolver) parentToken() (string, error) {
+	if r.config.Token != "" {
+		return r.config.Token, nil
+	}
+	token, err := ioutil.ReadFile(r.config.TokenFile)
+	if err != nil {
+		return "", errored.Errorf("failed to read vault token file. Error: %v", err)
+	}
+	return strings.TrimSpace(string(token)), nil
+}
+
+// createToken creates and returns the short-lived token
+func (r *Resolver) createToken() (string, error) {
+	parent, err := r.parentToken()
+	if err != nil {
+		return "", err
+	}
+	req := struct {
+		TTL         string   `json:"ttl"`
+		Policies    []string `json:"policies,omitempty"`
+		DisplayName string   `json:"display_name"`
+	}{
+		TTL:         fmt.Sprintf("%ds", r.config.TokenTTLSecs),
+		Policies:    r.config.Policies,
+		DisplayName: "clusterm-job",
+	}
+	resp := struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}{}
+	if err := r.request("POST", "auth/token/create", parent, req, &resp); err != nil {
+		return "", errored.Errorf("failed to create vault token. Error: %v", err)
+	}
+	return resp.Auth.ClientToken, nil
+}
+
+// readSecret reads the secret at the path. Both versions of kv secrets engine
+// are supported, the path is expected to include the data/ prefix for version 2
+func (r *Resolver) readSecret(path, token string) (map[string]interface{}, error) {
+	resp := struct {
+		Data map[string]interface{} `json:"data"`
+	}{}
+	if err := r.request("GET", path, token, nil, &resp); err != nil {
+		return nil, errored.Errorf("failed to read vault secret %q. Error: %v", path, err)
+	}
+	// version 2 of kv nests the secret under data along with it's metadata
+	if data, ok := resp.Data["data"].(map[string]interface{}); ok {
+		if _, ok := resp.Data["metadata"]; ok {
+			return data, nil
+		}
+	}
+	return resp.Data, nil
+}
+
+func (r *Resolver) request(method, rsrc, token string, req, resp interface{}) error {
+	var reqBody io.Reader
+	if req != nil {
+		var b bytes.Buffer
+		if err := json.NewEncoder(&b).Encode(req); err != nil {
+			return err
+		}
+		reqBody = &b
+	}
+	httpReq, err := http.NewRequest(method, strings.TrimSuffix(r.config.Addr, "/")+"/v1/"+rsrc, reqBody)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("X-Vault-Token", token)
+	if req != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	httpResp, err := r.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+
+	body, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return err
+	}
+	if httpResp.StatusCode != http.StatusOK && httpResp.StatusCode != http.StatusNoContent {
+		return errored.Errorf("status code %d unexpected. Response body: %q", httpResp.StatusCode, body)
+	}
+	if resp == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return errored.Errorf("failed to unmarshal response. Error: %v", err)
+	}
+	return nil
+}
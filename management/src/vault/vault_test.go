@@ -0,0 +1,106 @@
+// +build unittest
+
+package vault
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type vaultSuite struct {
+}
+
+var _ = Suite(&vaultSuite{})
+
+// fakeVault serves token create/revoke and kv reads. It records the requests
+// received as "<method> <path> <token>"
+func fakeVault(c *C) (*httptest.Server, *[]string) {
+	reqs := &[]string{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("X-Vault-Token")
+		*reqs = append(*reqs, r.Method+" "+r.URL.Path+" "+token)
+		switch r.URL.Path {
+		case "/v1/auth/token/create":
+			req := map[string]interface{}{}
+			c.Assert(json.NewDecoder(r.Body).Decode(&req), IsNil)
+			c.Assert(req["ttl"], Equals, "60s")
+			w.Write([]byte(`{"auth": {"client_token": "short-lived"}}`))
+		case "/v1/auth/token/revoke-self":
+			w.WriteHeader(http.StatusNoContent)
+		case "/v1/secret/registry":
+			if token != "short-lived" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Write([]byte(`{"data": {"user": "admin", "password": "secret", "port": 5000}}`))
+		case "/v1/kv/data/registry":
+			w.Write([]byte(`{"data": {"data": {"password": "secret2"}, "metadata": {"version": 1}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return ts, reqs
+}
+
+func (s *vaultSuite) TestParseRef(c *C) {
+	path, key, err := parseRef("vault:secret/registry#password")
+	c.Assert(err, IsNil)
+	c.Assert(path, Equals, "secret/registry")
+	c.Assert(key, Equals, "password")
+
+	for _, ref := range []string{"vault:secret/registry", "vault:#password", "vault:secret/registry#"} {
+		_, _, err := parseRef(ref)
+		c.Assert(err, NotNil, Commentf("ref: %s", ref))
+	}
+}
+
+func (s *vaultSuite) TestResolve(c *C) {
+	ts, reqs := fakeVault(c)
+	defer ts.Close()
+
+	r := NewResolver(Config{Addr: ts.URL, Token: "parent", TokenTTLSecs: 60})
+	c.Assert(r.IsRef("vault:secret/registry#password"), Equals, true)
+	c.Assert(r.IsRef("password"), Equals, false)
+
+	vals, err := r.Resolve([]string{"vault:secret/registry#password", "vault:secret/registry#port",
+		"vault:kv/data/registry#password"})
+	c.Assert(err, IsNil)
+	c.Assert(vals, DeepEquals, map[string]string{
+		"vault:secret/registry#password":  "secret",
+		"vault:secret/registry#port":      "5000",
+		"vault:kv/data/registry#password": "secret2",
+	})
+	// the secret is read once with the short-lived token, which is revoked after
+	c.Assert(*reqs, DeepEquals, []string{
+		"POST /v1/auth/token/create parent",
+		"GET /v1/secret/registry short-lived",
+		"GET /v1/kv/data/registry short-lived",
+		"POST /v1/auth/token/revoke-self short-lived",
+	})
+}
+
+func (s *vaultSuite) TestResolveFailures(c *C) {
+	ts, reqs := fakeVault(c)
+	defer ts.Close()
+
+	r := NewResolver(Config{Addr: ts.URL, Token: "parent", TokenTTLSecs: 60})
+	_, err := r.Resolve([]string{"vault:secret/registry#foo"})
+	c.Assert(err, ErrorMatches, `key "foo" not found in vault secret "secret/registry"`)
+
+	_, err = r.Resolve([]string{"vault:secret/missing#foo"})
+	c.Assert(err, ErrorMatches, `failed to read vault secret "secret/missing".*`)
+	c.Assert((*reqs)[len(*reqs)-1], Equals, "POST /v1/auth/token/revoke-self short-lived")
+
+	// invalid references fail before a token is created
+	*reqs = []string{}
+	_, err = r.Resolve([]string{"vault:secret/registry"})
+	c.Assert(err, NotNil)
+	c.Assert(*reqs, HasLen, 0)
+}